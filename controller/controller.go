@@ -6,10 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/chainguard-dev/clog"
 	"github.com/imjasonh/client-go2/generic"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -18,8 +25,20 @@ import (
 	"k8s.io/client-go/util/workqueue"
 )
 
+// defaultCacheSyncTimeout is how long Run waits for caches to sync if
+// Options.CacheSyncTimeout is not set.
+const defaultCacheSyncTimeout = 2 * time.Minute
+
+// defaultShutdownGracePeriod is how long Run waits for in-flight reconciles
+// when it stops if Options.ShutdownGracePeriod is not set.
+const defaultShutdownGracePeriod = 10 * time.Second
+
 // Options configures a Controller.
 type Options[T runtime.Object] struct {
+	// Name identifies the controller in logs and metrics.
+	// Defaults to the GroupKind of T, e.g. "ConfigMap".
+	Name string
+
 	// Namespace limits the controller to a specific namespace.
 	// If empty, the controller watches all namespaces.
 	Namespace string
@@ -39,6 +58,66 @@ type Options[T runtime.Object] struct {
 	// OwnedTypes is a list of resource types owned by the main resource type.
 	// When owned resources change, the controller will reconcile their owners.
 	OwnedTypes []OwnedType
+
+	// Predicates filter which updates of objects of type T are reconciled.
+	// An update is enqueued only if every predicate allows it. Adds and
+	// deletes are always enqueued. Owned types have their own Predicates.
+	Predicates []Predicate[T]
+
+	// Metrics receives queue and reconcile metrics, labelled by Name.
+	// Queue metrics are only recorded for the default queue, not a custom Queue.
+	Metrics Metrics
+
+	// TracerProvider, if set, is used to record a span for each reconcile.
+	// The span is available from the context passed to the Reconciler, and
+	// the controller's client records its API requests as child spans.
+	TracerProvider trace.TracerProvider
+
+	// InformerFactory, if set, provides the informers for the controller's
+	// resources and its OwnedTypes, so they share watches and caches with
+	// other consumers of the same factory. The controller starts the factory
+	// and waits for it to sync when it runs.
+	InformerFactory *generic.InformerFactory
+
+	// FinalizerName is the finalizer the controller manages for Reconcilers
+	// that implement Finalizer. Defaults to the resource and group of T
+	// qualified with the controller's Name, e.g.
+	// "widgets.example.com/widget-controller", or for core resources
+	// "pods.core/pod-controller".
+	FinalizerName string
+
+	// CacheSyncTimeout bounds how long Run waits for the informer caches of
+	// the controller's resources and its OwnedTypes to sync before starting
+	// workers. Run returns an error if they don't sync in time.
+	// Defaults to 2 minutes.
+	CacheSyncTimeout time.Duration
+
+	// LeaderElection, if set, makes replicas of the controller elect a
+	// leader with a Lease, and only the leader reconciles.
+	LeaderElection *LeaderElection
+
+	// ShutdownGracePeriod bounds how long Run waits for in-flight reconciles
+	// to finish once its context is canceled. Reconciles still running after
+	// it have their contexts canceled. Defaults to 10 seconds.
+	ShutdownGracePeriod time.Duration
+
+	// ReconcileTimeout, if set, bounds each call to the Reconciler: the
+	// context it is passed is canceled after ReconcileTimeout, and the error
+	// it returns is wrapped in ErrReconcileTimeout and retried with backoff.
+	// A reconciler that does not return by then is abandoned, and its
+	// changes to the object are discarded.
+	ReconcileTimeout time.Duration
+
+	// PermanentPanics makes a panic in the Reconciler a permanent error, so
+	// the object is not retried until it changes. By default panics are
+	// retried with backoff, like other errors. Panics are always recovered,
+	// logged with their stack, and reported to Metrics as OutcomePanic.
+	PermanentPanics bool
+
+	// DryRun sends all writes with dryRun=All, so the API server validates
+	// them without persisting anything. The change each reconcile would have
+	// made is logged as a diff instead.
+	DryRun bool
 }
 
 // OwnedType represents a type that is owned by the main resource
@@ -49,18 +128,47 @@ type OwnedType struct {
 	OwnerGVK schema.GroupVersionKind
 	// IsController indicates if we should only track controller references
 	IsController bool
+	// Predicates filter which updates of owned resources enqueue their
+	// owners. An update is enqueued only if every predicate allows it.
+	Predicates []Predicate[runtime.Object]
 }
 
 // Controller manages the reconciliation loop for resources of type T.
 type Controller[T runtime.Object] struct {
+	name         string
 	client       generic.Client[T]
 	reconciler   Reconciler[T]
 	queue        workqueue.TypedRateLimitingInterface[string]
+	tracker      *trackingQueue
 	namespace    string
 	concurrency  int
 	deepCopyFunc func(T) T
 	ownedTypes   []OwnedType
+	predicates   []Predicate[T]
 	ownedListers map[schema.GroupVersionKind]*generic.Lister[runtime.Object]
+	lister       *generic.Lister[T]
+	metrics      Metrics
+	tracer       trace.Tracer
+	dryRun       bool
+	factory      *generic.InformerFactory
+	finalizer    string
+
+	cacheSyncTimeout time.Duration
+	synced           atomic.Bool
+	leaderElection   *LeaderElection
+	elected          bool
+	leading          atomic.Bool
+
+	shutdownGracePeriod time.Duration
+	reconcileTimeout    time.Duration
+	permanentPanics     bool
+	started             atomic.Bool
+	workers             atomic.Int32
+	stopping            atomic.Bool
+	running             sync.WaitGroup
+	mu                  sync.Mutex
+	stopped             bool
+	cancelWork          context.CancelFunc
 }
 
 // New creates a new Controller with the given client, reconciler, and options.
@@ -72,40 +180,126 @@ func New[T runtime.Object](client generic.Client[T], reconciler Reconciler[T], o
 	if opts.Concurrency < 1 {
 		opts.Concurrency = 1
 	}
+	if opts.Name == "" {
+		opts.Name = client.GVK().GroupKind().String()
+	}
 	if opts.Queue == nil {
-		opts.Queue = workqueue.NewTypedRateLimitingQueue[string](workqueue.DefaultTypedControllerRateLimiter[string]())
+		config := workqueue.TypedRateLimitingQueueConfig[string]{Name: opts.Name}
+		if opts.Metrics != nil {
+			config.MetricsProvider = opts.Metrics
+		}
+		opts.Queue = workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](), config)
+	}
+	if opts.CacheSyncTimeout <= 0 {
+		opts.CacheSyncTimeout = defaultCacheSyncTimeout
+	}
+	if opts.ShutdownGracePeriod <= 0 {
+		opts.ShutdownGracePeriod = defaultShutdownGracePeriod
+	}
+	if opts.FinalizerName == "" {
+		opts.FinalizerName = defaultFinalizerName(client.GVR().GroupResource(), opts.Name)
+	}
+	if opts.DryRun {
+		client = client.DryRun()
+	}
+	tp := opts.TracerProvider
+	if tp != nil {
+		client = client.WithTracerProvider(tp)
+	} else {
+		tp = noop.NewTracerProvider()
 	}
 
+	tracker := newTrackingQueue(opts.Queue)
+
 	return &Controller[T]{
+		name:         opts.Name,
 		client:       client,
 		reconciler:   reconciler,
-		queue:        opts.Queue,
+		queue:        tracker,
+		tracker:      tracker,
 		namespace:    opts.Namespace,
 		concurrency:  opts.Concurrency,
 		ownedTypes:   opts.OwnedTypes,
+		predicates:   opts.Predicates,
 		deepCopyFunc: opts.DeepCopyFunc,
 		ownedListers: make(map[schema.GroupVersionKind]*generic.Lister[runtime.Object]),
+		metrics:      opts.Metrics,
+		tracer:       tp.Tracer(tracerName),
+		dryRun:       opts.DryRun,
+		factory:      opts.InformerFactory,
+		finalizer:    opts.FinalizerName,
+
+		cacheSyncTimeout: opts.CacheSyncTimeout,
+		leaderElection:   opts.LeaderElection,
+		elected:          opts.LeaderElection != nil,
+
+		shutdownGracePeriod: opts.ShutdownGracePeriod,
+		reconcileTimeout:    opts.ReconcileTimeout,
+		permanentPanics:     opts.PermanentPanics,
 	}
 }
 
+// Name returns the controller's name.
+func (c *Controller[T]) Name() string {
+	return c.name
+}
+
+// HasSynced returns true once Run has synced the controller's caches and is
+// ready to reconcile, including while it waits to be elected leader. It can
+// be used as a readiness check.
+func (c *Controller[T]) HasSynced() bool {
+	return c.synced.Load()
+}
+
 // Run starts the controller and blocks until the context is canceled.
+//
+// When the context is canceled, the controller stops taking new items from
+// its queue and waits for in-flight reconciles to finish, up to its
+// ShutdownGracePeriod, before canceling their contexts. Run returns once
+// every worker has stopped.
 func (c *Controller[T]) Run(ctx context.Context) error {
-	defer c.queue.ShutDown()
+	defer c.shutdown()
 
-	clog.InfoContext(ctx, "starting controller", "concurrency", c.concurrency)
+	clog.InfoContext(ctx, "starting controller", "name", c.name, "concurrency", c.concurrency)
 
-	// Start the informer
+	synced, err := c.startInformers(ctx)
+	if err != nil {
+		return err
+	}
+	if c.factory != nil {
+		c.factory.Start(ctx)
+	}
+	if err := waitForCacheSync(ctx, "controller "+c.name, c.cacheSyncTimeout, synced, c.factory); err != nil {
+		return err
+	}
+
+	c.setSynced(true)
+	defer c.setSynced(false)
+
+	return runWorkers(ctx, "controller "+c.name, c.leaderElection, c.setLeading, []managed{c})
+}
+
+// startInformers starts watching the controller's resources and its owned
+// types, returning functions that report when each informer has synced.
+// Informers from the controller's InformerFactory are not started until the
+// factory is.
+func (c *Controller[T]) startInformers(ctx context.Context) ([]cache.InformerSynced, error) {
 	handler := generic.InformerHandler[T]{
 		OnAdd: func(key string, obj T) {
 			clog.DebugContext(ctx, "resource added", "key", key)
 			c.queue.Add(key)
 		},
 		OnUpdate: func(key string, oldObj, newObj T) {
+			if !allowed(c.predicates, oldObj, newObj) {
+				clog.DebugContext(ctx, "resource update filtered by predicates", "key", key)
+				return
+			}
 			clog.DebugContext(ctx, "resource updated", "key", key)
 			c.queue.Add(key)
 		},
 		OnDelete: func(key string, obj T) {
 			clog.DebugContext(ctx, "resource deleted", "key", key)
+			c.tracker.deleted(key)
 			c.queue.Add(key)
 		},
 		OnError: func(obj any, err error) {
@@ -113,43 +307,186 @@ func (c *Controller[T]) Run(ctx context.Context) error {
 		},
 	}
 
-	opts := &generic.InformOptions{}
-	if c.namespace != "" {
-		opts.ListOptions.FieldSelector = fmt.Sprintf("metadata.namespace=%s", c.namespace)
-	}
+	opts := &generic.InformOptions[T]{Namespace: c.namespace}
 
-	// Start informer in background
-	go func() {
-		if _, err := c.client.Inform(ctx, handler, opts); err != nil {
-			clog.ErrorContext(ctx, "failed to start informer", "error", err)
+	var informer *generic.Informer[T]
+	if c.factory != nil {
+		informer = c.client.SharedInformer(c.factory, opts)
+		if _, err := informer.AddHandler(handler); err != nil {
+			return nil, fmt.Errorf("failed to add event handler: %w", err)
 		}
-	}()
+	} else {
+		// Start informer in background
+		opts.NoWaitForSync = true
+		var err error
+		if informer, err = c.client.Inform(ctx, handler, opts); err != nil {
+			return nil, fmt.Errorf("failed to start informer: %w", err)
+		}
+	}
+	c.lister = informer.Lister()
+
+	synced := []cache.InformerSynced{informer.HasSynced}
 
 	// Start watching owned resources
 	for _, owned := range c.ownedTypes {
-		lister, err := WatchOwned(ctx, c, owned.Client, owned.IsController)
+		ownedInformer, err := watchOwned(ctx, c, owned.Client, owned.IsController, owned.Predicates)
 		if err != nil {
-			return fmt.Errorf("failed to watch owned resources: %w", err)
+			return nil, fmt.Errorf("failed to watch owned resources: %w", err)
+		}
+		c.ownedListers[owned.OwnerGVK] = ownedInformer.Lister()
+		synced = append(synced, ownedInformer.HasSynced)
+	}
+	return synced, nil
+}
+
+// waitForCacheSync waits for every cache, and every informer started by
+// factory if it is not nil, to sync, so workers never reconcile against a
+// partial view of the world. The factory's informers include those of owned
+// types watched with WatchOwned.
+func waitForCacheSync(ctx context.Context, name string, timeout time.Duration, synced []cache.InformerSynced, factory *generic.InformerFactory) error {
+	clog.InfoContext(ctx, "waiting for cache sync", "name", name, "timeout", timeout)
+	syncCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ok := cache.WaitForNamedCacheSync(name, syncCtx.Done(), synced...)
+	if ok && factory != nil {
+		ok = factory.WaitForCacheSync(syncCtx) == nil
+	}
+	if !ok {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s stopped before caches synced: %w", name, ctx.Err())
+		}
+		return fmt.Errorf("%s: caches did not sync within %v", name, timeout)
+	}
+	return nil
+}
+
+// setSynced records whether the controller's caches have synced.
+func (c *Controller[T]) setSynced(synced bool) {
+	c.synced.Store(synced)
+}
+
+// setLeading records whether the controller is the elected leader.
+func (c *Controller[T]) setLeading(leading bool) {
+	c.leading.Store(leading)
+}
+
+// shutdown stops the controller's queue, so its workers exit.
+func (c *Controller[T]) shutdown() {
+	c.queue.ShutDown()
+}
+
+// runWorkers runs the workers of controllers until ctx is done, or, with
+// leader election, while leading. When ctx is done, the workers are stopped
+// gracefully before leadership is released, so that no other replica starts
+// reconciling while they finish.
+func runWorkers(ctx context.Context, name string, le *LeaderElection, setLeading func(bool), controllers []managed) error {
+	start := func(ctx context.Context) {
+		for _, c := range controllers {
+			c.startWorkers(ctx)
+		}
+	}
+	stop := func() {
+		var wg sync.WaitGroup
+		for _, c := range controllers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.stopWorkers(ctx)
+			}()
 		}
-		c.ownedListers[owned.OwnerGVK] = lister
+		wg.Wait()
 	}
 
-	// Wait for cache sync
-	clog.InfoContext(ctx, "waiting for cache sync")
-	time.Sleep(time.Second) // Simple wait for now
+	// Workers outlive ctx, so in-flight reconciles can finish after it is
+	// canceled.
+	workCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
 
-	// Start workers
+	if le == nil {
+		start(workCtx)
+		<-ctx.Done()
+		clog.InfoContext(ctx, "shutting down", "name", name)
+		stop()
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			clog.InfoContext(ctx, "shutting down", "name", name)
+			stop()
+			cancel() // Release the lease.
+		case <-workCtx.Done():
+		}
+	}()
+	err := le.run(workCtx, name, setLeading, start)
+	// If leadership was lost, the workers' contexts are already canceled.
+	stop()
+	cancel()
+	<-stopped
+	return err
+}
+
+// startWorkers starts the controller's workers, which process items until
+// ctx is done or stopWorkers is called. It does nothing once stopWorkers has
+// been called, which can race with it when leadership is acquired during
+// shutdown.
+func (c *Controller[T]) startWorkers(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+	ctx, c.cancelWork = context.WithCancel(ctx)
+	c.started.Store(true)
+	c.workers.Add(int32(c.concurrency))
+	c.running.Add(c.concurrency)
 	for i := 0; i < c.concurrency; i++ {
 		go c.runWorker(ctx)
 	}
+}
 
-	<-ctx.Done()
-	clog.InfoContext(ctx, "shutting down controller")
-	return nil
+// stopWorkers stops the controller's workers from taking new items, and
+// waits for in-flight reconciles to finish. Those still running after the
+// shutdown grace period have their contexts canceled, and stopWorkers
+// returns once they return.
+func (c *Controller[T]) stopWorkers(ctx context.Context) {
+	// No workers start once stopping, so running is not added to while it
+	// is waited on.
+	c.mu.Lock()
+	c.stopped = true
+	c.mu.Unlock()
+	// Workers exit as the queue drains, which is not a failure.
+	c.stopping.Store(true)
+	c.queue.ShutDown()
+
+	done := make(chan struct{})
+	go func() {
+		c.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return
+	case <-time.After(c.shutdownGracePeriod):
+	}
+
+	clog.WarnContext(ctx, "in-flight reconciles did not finish within the grace period, canceling them",
+		"name", c.name, "gracePeriod", c.shutdownGracePeriod)
+	c.mu.Lock()
+	if c.cancelWork != nil {
+		c.cancelWork()
+	}
+	c.mu.Unlock()
+	<-done
 }
 
 // runWorker processes items from the queue.
 func (c *Controller[T]) runWorker(ctx context.Context) {
+	defer c.running.Done()
+	defer c.workers.Add(-1)
 	for c.processNextItem(ctx) {
 		select {
 		case <-ctx.Done():
@@ -166,8 +503,16 @@ func (c *Controller[T]) processNextItem(ctx context.Context) bool {
 		return false
 	}
 	defer c.queue.Done(key)
+	if c.queue.ShuttingDown() {
+		// The controller is stopping, and takes no new work.
+		return false
+	}
 
-	if err := c.processItem(ctx, key); err != nil {
+	start := time.Now()
+	err := c.processItem(ctx, key)
+	c.observeReconcile(err, time.Since(start))
+	c.tracker.observe(key, err)
+	if err != nil {
 		c.handleProcessError(ctx, key, err)
 		return true
 	}
@@ -178,23 +523,43 @@ func (c *Controller[T]) processNextItem(ctx context.Context) bool {
 }
 
 // processItem fetches the object and calls the reconciler.
-func (c *Controller[T]) processItem(ctx context.Context, key string) error {
+func (c *Controller[T]) processItem(ctx context.Context, key string) (err error) {
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		return fmt.Errorf("invalid key format: %w", err)
 	}
 
+	ctx, span := c.startItemSpan(ctx, key, namespace)
+	defer func() { endItemSpan(span, err) }()
+
 	// Fetch current object
-	current, err := c.client.Get(ctx, namespace, name, nil)
+	current, err := c.get(ctx, namespace, name)
+	if apierrors.IsNotFound(err) {
+		return c.reconcileDeleted(ctx, key)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get object: %w", err)
 	}
+	if meta := c.getObjectMeta(current); meta != nil {
+		span.SetAttributes(attribute.Int64("k8s.object.generation", meta.Generation))
+	}
 
 	// Deep copy to preserve original for comparison
 	original := c.deepCopy(current)
 
+	if f, ok := c.reconciler.(Finalizer[T]); ok {
+		if meta := c.getObjectMeta(current); meta != nil && meta.DeletionTimestamp != nil {
+			return c.finalize(ctx, key, f, original, current)
+		}
+		if original, err = c.ensureFinalizer(ctx, original, current); err != nil {
+			return err
+		}
+	}
+
 	// Call user's reconciler - they modify 'current' in place
-	if err := c.reconciler.Reconcile(ctx, current); err != nil {
+	if err := c.invoke(ctx, key, func(ctx context.Context) error {
+		return c.reconciler.Reconcile(ctx, current)
+	}); err != nil {
 		// Don't update if reconciler returned error
 		return err
 	}
@@ -203,6 +568,110 @@ func (c *Controller[T]) processItem(ctx context.Context, key string) error {
 	return c.updateIfNeeded(ctx, original, current)
 }
 
+// get returns a copy of the named object from the informer cache, or from
+// the API server if the controller has not started its informer.
+func (c *Controller[T]) get(ctx context.Context, namespace, name string) (T, error) {
+	if c.lister == nil {
+		return c.client.Get(ctx, namespace, name, nil)
+	}
+	var obj T
+	var err error
+	if namespace == "" {
+		obj, err = c.lister.Get(name)
+	} else {
+		obj, err = c.lister.ByNamespace(namespace).Get(name)
+	}
+	if err != nil {
+		return obj, err
+	}
+	// The reconciler modifies the object in place, so it must not be the
+	// cached one.
+	return c.deepCopy(obj), nil
+}
+
+// reconcileDeleted handles a key whose object no longer exists, calling the
+// reconciler's ReconcileDeleted if it implements DeletionReconciler.
+func (c *Controller[T]) reconcileDeleted(ctx context.Context, key string) error {
+	dr, ok := c.reconciler.(DeletionReconciler[T])
+	if !ok {
+		clog.DebugContext(ctx, "object no longer exists", "key", key)
+		return nil
+	}
+	return c.invoke(ctx, key, func(ctx context.Context) error {
+		return dr.ReconcileDeleted(ctx, key)
+	})
+}
+
+// invoke calls the reconciler with fn, recovering from panics and bounding
+// it by the controller's ReconcileTimeout.
+//
+// With a timeout, fn runs in its own goroutine so that a reconciler that
+// ignores its context cannot hold the worker past the deadline. Such a
+// reconciler is abandoned: invoke returns a timeout error, so the caller
+// does not apply any of its changes, and it is left to finish on its own.
+// A reconciler whose context is canceled, as on shutdown, is likewise
+// abandoned if it doesn't return by its deadline.
+func (c *Controller[T]) invoke(ctx context.Context, key string, fn func(context.Context) error) error {
+	if c.reconcileTimeout <= 0 {
+		return c.call(ctx, key, fn)
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, c.reconcileTimeout, ErrReconcileTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- c.call(ctx, key, fn) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		if !errors.Is(context.Cause(ctx), ErrReconcileTimeout) {
+			// Canceled by the caller, as on shutdown, which waits for
+			// reconciles to return: give the reconciler the rest of its
+			// timeout to do so.
+			deadline, _ := ctx.Deadline()
+			select {
+			case err = <-done:
+			case <-time.After(time.Until(deadline)):
+				clog.WarnContext(ctx, "abandoning reconciler that ignored cancellation", "key", key, "timeout", c.reconcileTimeout)
+				return fmt.Errorf("reconciler did not return within %v of cancellation: %w", c.reconcileTimeout, ctx.Err())
+			}
+			break
+		}
+		select {
+		case err = <-done:
+		default:
+			clog.WarnContext(ctx, "abandoning reconciler that ignored its timeout", "key", key, "timeout", c.reconcileTimeout)
+			return fmt.Errorf("%w after %v: %w", ErrReconcileTimeout, c.reconcileTimeout, ctx.Err())
+		}
+	}
+	if err != nil && errors.Is(context.Cause(ctx), ErrReconcileTimeout) && !IsTimeoutError(err) {
+		err = fmt.Errorf("%w after %v: %w", ErrReconcileTimeout, c.reconcileTimeout, err)
+	}
+	return err
+}
+
+// call calls fn, returning a panic in it as an error.
+func (c *Controller[T]) call(ctx context.Context, key string, fn func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicked(ctx, key, r)
+			if c.permanentPanics {
+				err = PermanentError(err)
+			}
+		}
+	}()
+	return fn(ctx)
+}
+
+// panicked logs a panic recovered from the reconciler of key, with the
+// stack of the panicking goroutine, and returns it as an error.
+func panicked(ctx context.Context, key string, r any) error {
+	err := &panicError{value: r, stack: debug.Stack()}
+	clog.ErrorContext(ctx, "recovered from panic in reconciler", "key", key, "panic", r, "stack", string(err.stack))
+	return err
+}
+
 // updateIfNeeded compares the original and current objects and updates if necessary.
 func (c *Controller[T]) updateIfNeeded(ctx context.Context, original, current T) error {
 	// Extract metadata for both objects
@@ -273,6 +742,8 @@ func (c *Controller[T]) updateMetadataWithRetry(ctx context.Context, original, c
 			return err
 		}
 
+		before := c.deepCopy(latest)
+
 		// Copy metadata from current to latest
 		latestMeta := c.getObjectMeta(latest)
 		if latestMeta == nil {
@@ -304,7 +775,9 @@ func (c *Controller[T]) updateMetadataWithRetry(ctx context.Context, original, c
 
 		// Update the object
 		updated, err := c.client.Update(ctx, currMeta.Namespace, latest, nil)
-		if err == nil {
+		if err == nil && c.dryRun {
+			c.logDryRun(ctx, "metadata", before, updated)
+		} else if err == nil {
 			clog.DebugContext(ctx, "successfully updated metadata",
 				"namespace", currMeta.Namespace,
 				"name", currMeta.Name,
@@ -328,13 +801,18 @@ func (c *Controller[T]) updateStatusWithRetry(ctx context.Context, original, cur
 			return err
 		}
 
+		before := c.deepCopy(latest)
+
 		// Copy status from current to latest
 		if err := c.copyStatus(current, latest); err != nil {
 			return fmt.Errorf("failed to copy status: %w", err)
 		}
 
 		// Update status
-		_, err = c.client.UpdateStatus(ctx, currMeta.Namespace, latest, nil)
+		updated, err := c.client.UpdateStatus(ctx, currMeta.Namespace, latest, nil)
+		if err == nil && c.dryRun {
+			c.logDryRun(ctx, "status", before, updated)
+		}
 		return err
 	})
 }
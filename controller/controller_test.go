@@ -0,0 +1,814 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imjasonh/client-go2/generic"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// deletionReconciler records the objects it reconciles and the keys of the
+// objects it is told were deleted.
+type deletionReconciler struct {
+	mu         sync.Mutex
+	reconciled []string
+	deleted    []string
+}
+
+func (r *deletionReconciler) Reconcile(_ context.Context, cm *corev1.ConfigMap) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reconciled = append(r.reconciled, cm.Namespace+"/"+cm.Name)
+	return nil
+}
+
+func (r *deletionReconciler) ReconcileDeleted(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted = append(r.deleted, key)
+	return nil
+}
+
+func (r *deletionReconciler) counts() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.reconciled), len(r.deleted)
+}
+
+// apiServer is an in-memory API server for tests. It stores objects as JSON
+// by path, and serves gets, lists, creates, updates and deletes of them, with
+// resourceVersions and optimistic concurrency on updates. Updates of the
+// status subresource change only the object's status. Writes with
+// dryRun are validated but not stored. Each watch request sends a pipe on
+// watches, through which the test writes events; the pipe is closed when
+// the request ends.
+type apiServer struct {
+	watches chan *io.PipeWriter
+	// failWrites fails every write with 500 while set.
+	failWrites atomic.Bool
+
+	mu       sync.Mutex
+	objects  map[string]map[string]any
+	rv       int
+	status   int                      // if set, every request fails with it
+	held     map[string]chan struct{} // lists of these paths wait for release
+	requests []string                 // "METHOD path" of each request but watches
+	writes   []apiWrite               // each successful write
+}
+
+// apiWrite is a write served by an apiServer, including dry runs.
+type apiWrite struct {
+	// request is the method and URL of the write, e.g. "PUT /api/v1/...?dryRun=All".
+	request string
+	// object is the object written, as JSON.
+	object []byte
+}
+
+func newAPIServer() *apiServer {
+	return &apiServer{
+		watches: make(chan *io.PipeWriter, 100),
+		objects: map[string]map[string]any{},
+		held:    map[string]chan struct{}{},
+	}
+}
+
+// newConfigMapServer returns an apiServer holding a single ConfigMap,
+// default/cm.
+func newConfigMapServer() *apiServer {
+	s := newAPIServer()
+	s.set("/api/v1/namespaces/default/configmaps/cm", &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default", Generation: 3},
+	})
+	return s
+}
+
+func (s *apiServer) config() *rest.Config {
+	return &rest.Config{Host: "http://localhost", Transport: s}
+}
+
+// set stores obj at path, giving it the next resourceVersion.
+func (s *apiServer) set(path string, obj any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(path, mustUnstructured(obj))
+}
+
+// get decodes the object at path into obj, returning false if there is none.
+func (s *apiServer) get(path string, obj any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.objects[path]
+	if !ok {
+		return false
+	}
+	b, _ := json.Marshal(stored)
+	return json.Unmarshal(b, obj) == nil
+}
+
+// failAll makes every request fail with code.
+func (s *apiServer) failAll(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = code
+}
+
+// holdLists makes lists of the collection at path wait until release is
+// called.
+func (s *apiServer) holdLists(path string) (release func()) {
+	ch := make(chan struct{})
+	s.mu.Lock()
+	s.held[path] = ch
+	s.mu.Unlock()
+	return sync.OnceFunc(func() { close(ch) })
+}
+
+// requestLog returns the method and path of every request but watches.
+func (s *apiServer) requestLog() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// writeLog returns every successful write.
+func (s *apiServer) writeLog() []apiWrite {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.writes)
+}
+
+func (s *apiServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Query().Get("watch") == "true" {
+		r, w := io.Pipe()
+		select {
+		case s.watches <- w:
+		default:
+		}
+		go func() {
+			<-req.Context().Done()
+			w.Close()
+		}()
+		return apiResponse(req, http.StatusOK, r), nil
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req.Method+" "+req.URL.Path)
+	held := s.held[req.URL.Path]
+	s.mu.Unlock()
+	if held != nil && req.Method == http.MethodGet {
+		select {
+		case <-held:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status != 0 {
+		return apiStatus(req, s.status), nil
+	}
+	if req.Method != http.MethodGet && s.failWrites.Load() {
+		return apiStatus(req, http.StatusInternalServerError), nil
+	}
+
+	path, collection := objectPath(req.URL.Path)
+	var body map[string]any
+	if req.Body != nil && req.Method != http.MethodGet && req.Method != http.MethodDelete {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+	}
+	dryRun := req.URL.Query().Has("dryRun")
+	switch {
+	case req.Method == http.MethodGet && collection:
+		return apiJSON(req, http.StatusOK, s.list(path)), nil
+
+	case req.Method == http.MethodGet:
+		if obj, ok := s.objects[path]; ok {
+			return apiJSON(req, http.StatusOK, obj), nil
+		}
+		return apiStatus(req, http.StatusNotFound), nil
+
+	case req.Method == http.MethodPost && collection:
+		meta, _ := body["metadata"].(map[string]any)
+		name, _ := meta["name"].(string)
+		path += "/" + name
+		if _, ok := s.objects[path]; ok {
+			return apiStatus(req, http.StatusConflict), nil
+		}
+		if !dryRun {
+			s.store(path, body)
+		}
+		s.record(req, body)
+		return apiJSON(req, http.StatusCreated, body), nil
+
+	case req.Method == http.MethodPut:
+		stored, ok := s.objects[path]
+		if !ok {
+			return apiStatus(req, http.StatusNotFound), nil
+		}
+		if rv := resourceVersion(body); rv != "" && rv != resourceVersion(stored) {
+			return apiStatus(req, http.StatusConflict), nil
+		}
+		if strings.HasSuffix(req.URL.Path, "/status") {
+			status := body["status"]
+			body = mustUnstructured(stored)
+			body["status"] = status
+		}
+		if !dryRun {
+			s.store(path, body)
+		}
+		s.record(req, body)
+		return apiJSON(req, http.StatusOK, body), nil
+
+	case req.Method == http.MethodDelete:
+		if _, ok := s.objects[path]; !ok {
+			return apiStatus(req, http.StatusNotFound), nil
+		}
+		if !dryRun {
+			delete(s.objects, path)
+		}
+		return apiJSON(req, http.StatusOK, map[string]any{"kind": "Status", "apiVersion": "v1", "status": "Success"}), nil
+	}
+	return apiStatus(req, http.StatusMethodNotAllowed), nil
+}
+
+// store stores obj at path with the next resourceVersion, keeping the kind
+// of any object it replaces. s.mu must be held.
+func (s *apiServer) store(path string, obj map[string]any) {
+	if old, ok := s.objects[path]; ok && obj["kind"] == nil {
+		obj["kind"], obj["apiVersion"] = old["kind"], old["apiVersion"]
+	}
+	s.rv++
+	meta, _ := obj["metadata"].(map[string]any)
+	if meta == nil {
+		meta = map[string]any{}
+		obj["metadata"] = meta
+	}
+	meta["resourceVersion"] = strconv.Itoa(s.rv)
+	s.objects[path] = obj
+}
+
+// record records a write of obj. s.mu must be held.
+func (s *apiServer) record(req *http.Request, obj map[string]any) {
+	b, _ := json.Marshal(obj)
+	s.writes = append(s.writes, apiWrite{request: req.Method + " " + req.URL.RequestURI(), object: b})
+}
+
+// list returns a list of the objects in the collection at path, which is
+// every namespace's if path is not namespaced. s.mu must be held.
+func (s *apiServer) list(path string) map[string]any {
+	prefix, resource := path[:strings.LastIndex(path, "/")], path[strings.LastIndex(path, "/")+1:]
+	var paths []string
+	for p := range s.objects {
+		dir := p[:strings.LastIndex(p, "/")]
+		if dir == path || (!strings.Contains(prefix, "/namespaces/") && strings.HasPrefix(dir, prefix+"/namespaces/") && strings.HasSuffix(dir, "/"+resource) && strings.Count(dir[len(prefix):], "/") == 3) {
+			paths = append(paths, p)
+		}
+	}
+	slices.Sort(paths)
+	kind, apiVersion := "List", "v1"
+	items := []any{}
+	for _, p := range paths {
+		obj := s.objects[p]
+		if k, ok := obj["kind"].(string); ok {
+			kind, apiVersion = k+"List", obj["apiVersion"].(string)
+		}
+		items = append(items, obj)
+	}
+	return map[string]any{
+		"kind":       kind,
+		"apiVersion": apiVersion,
+		"metadata":   map[string]any{"resourceVersion": strconv.Itoa(s.rv)},
+		"items":      items,
+	}
+}
+
+// objectPath returns the path of the object a request path refers to,
+// without any status subresource, and whether it is a collection.
+func objectPath(path string) (string, bool) {
+	path = strings.TrimSuffix(path, "/status")
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if i := slices.Index(parts, "namespaces"); i >= 0 && len(parts) > i+2 {
+		return path, len(parts) == i+3
+	}
+	// Skip "api/v1" or "apis/group/version".
+	if parts[0] == "apis" {
+		return path, len(parts) == 4
+	}
+	return path, len(parts) == 3
+}
+
+func resourceVersion(obj map[string]any) string {
+	meta, _ := obj["metadata"].(map[string]any)
+	rv, _ := meta["resourceVersion"].(string)
+	return rv
+}
+
+func mustUnstructured(obj any) map[string]any {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func apiJSON(req *http.Request, code int, obj any) *http.Response {
+	b, _ := json.Marshal(obj)
+	return apiResponse(req, code, io.NopCloser(bytes.NewReader(b)))
+}
+
+func apiStatus(req *http.Request, code int) *http.Response {
+	reason := map[int]metav1.StatusReason{
+		http.StatusNotFound:            metav1.StatusReasonNotFound,
+		http.StatusConflict:            metav1.StatusReasonConflict,
+		http.StatusForbidden:           metav1.StatusReasonForbidden,
+		http.StatusMethodNotAllowed:    metav1.StatusReasonMethodNotAllowed,
+		http.StatusInternalServerError: metav1.StatusReasonInternalError,
+	}[code]
+	if code == http.StatusConflict && req.Method == http.MethodPost {
+		reason = metav1.StatusReasonAlreadyExists
+	}
+	return apiJSON(req, code, metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Reason:   reason,
+		Code:     int32(code),
+	})
+}
+
+func apiResponse(req *http.Request, code int, body io.ReadCloser) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       body,
+		Request:    req,
+	}
+}
+
+func TestControllerReconcilesDeletes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	r := &deletionReconciler{}
+	ctrl := New(client, r, &Options[*corev1.ConfigMap]{Name: "test"})
+
+	done := make(chan error)
+	go func() { done <- ctrl.Run(ctx) }()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		for !cond() {
+			select {
+			case <-ctx.Done():
+				t.Fatalf("timed out waiting for %s", what)
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+	waitFor("initial reconcile", func() bool { n, _ := r.counts(); return n == 1 })
+
+	watch := <-server.watches
+	if _, err := io.WriteString(watch, `{"type":"DELETED","object":{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"cm","namespace":"default","resourceVersion":"2"}}}`+"\n"); err != nil {
+		t.Fatalf("writing watch event failed: %v", err)
+	}
+	waitFor("ReconcileDeleted", func() bool { _, n := r.counts(); return n == 1 })
+
+	r.mu.Lock()
+	if r.deleted[0] != "default/cm" {
+		t.Errorf("ReconcileDeleted called with %q, want default/cm", r.deleted[0])
+	}
+	if len(r.reconciled) != 1 {
+		t.Errorf("Reconcile called %d times, want 1", len(r.reconciled))
+	}
+	r.mu.Unlock()
+
+	if got, want := server.requestLog(), []string{"GET /api/v1/configmaps"}; !slices.Equal(got, want) {
+		t.Errorf("expected only the informer's list, got %v", got)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+}
+
+func TestProcessItemDeleted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, newConfigMapServer().config())
+	informer, err := client.Inform(ctx, generic.InformerHandler[*corev1.ConfigMap]{}, &generic.InformOptions[*corev1.ConfigMap]{NoWaitForSync: true})
+	if err != nil {
+		t.Fatalf("Inform failed: %v", err)
+	}
+	informer.Stop()
+
+	// A reconciler that doesn't implement DeletionReconciler ignores deletes.
+	called := false
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+		called = true
+		return nil
+	}), nil)
+	ctrl.lister = informer.Lister()
+	if err := ctrl.processItem(ctx, "default/gone"); err != nil {
+		t.Errorf("processItem of deleted object failed: %v", err)
+	}
+	if called {
+		t.Error("Reconcile was called for a deleted object")
+	}
+
+	r := &deletionReconciler{}
+	ctrl = New(client, r, nil)
+	ctrl.lister = informer.Lister()
+	if err := ctrl.processItem(ctx, "default/gone"); err != nil {
+		t.Errorf("processItem of deleted object failed: %v", err)
+	}
+	if len(r.deleted) != 1 || r.deleted[0] != "default/gone" {
+		t.Errorf("ReconcileDeleted calls = %v, want [default/gone]", r.deleted)
+	}
+}
+
+func TestProcessItemCopiesCachedObject(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := newConfigMapServer()
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	informer, err := client.Inform(ctx, generic.InformerHandler[*corev1.ConfigMap]{}, nil)
+	if err != nil {
+		t.Fatalf("Inform failed: %v", err)
+	}
+
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(_ context.Context, cm *corev1.ConfigMap) error {
+		cm.Data = map[string]string{"mutated": "true"}
+		return nil
+	}), &Options[*corev1.ConfigMap]{DeepCopyFunc: (*corev1.ConfigMap).DeepCopy})
+	ctrl.lister = informer.Lister()
+	if err := ctrl.processItem(ctx, "default/cm"); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+	cached, err := informer.Lister().ByNamespace("default").Get("cm")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if cached.Data != nil {
+		t.Error("the reconciler modified the cached object")
+	}
+}
+
+func TestRunWaitsForCacheSync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	release := server.holdLists("/api/v1/configmaps")
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	r := &deletionReconciler{}
+	ctrl := New(client, r, &Options[*corev1.ConfigMap]{Name: "test"})
+
+	done := make(chan error)
+	go func() { done <- ctrl.Run(ctx) }()
+
+	time.Sleep(200 * time.Millisecond)
+	if n, _ := r.counts(); n != 0 || ctrl.HasSynced() {
+		t.Fatalf("expected no reconciles and not synced before the cache synced, got %d reconciles, synced=%t", n, ctrl.HasSynced())
+	}
+	release()
+	for n, _ := r.counts(); n == 0 || !ctrl.HasSynced(); n, _ = r.counts() {
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the controller to sync")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+	if ctrl.HasSynced() {
+		t.Error("expected HasSynced to be false after Run returned")
+	}
+}
+
+func TestRunWaitsForOwnedCacheSync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	release := server.holdLists("/api/v1/secrets")
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	secrets := generic.NewClientGVR[*corev1.Secret](schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, server.config())
+	r := &deletionReconciler{}
+	ctrl := New(client, r, &Options[*corev1.ConfigMap]{Name: "test", InformerFactory: generic.NewInformerFactory()})
+	if _, err := WatchOwned(ctx, ctrl, secrets, false); err != nil {
+		t.Fatalf("WatchOwned failed: %v", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- ctrl.Run(ctx) }()
+
+	time.Sleep(200 * time.Millisecond)
+	if n, _ := r.counts(); n != 0 || ctrl.HasSynced() {
+		t.Fatalf("expected no reconciles and not synced before the owned cache synced, got %d reconciles, synced=%t", n, ctrl.HasSynced())
+	}
+	release()
+	waitUntil(t, ctx, "the controller to reconcile", func() bool { n, _ := r.counts(); return n > 0 && ctrl.HasSynced() })
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+}
+
+func TestRunCacheSyncTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newAPIServer()
+	server.failAll(http.StatusForbidden)
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	ctrl := New(client, &deletionReconciler{}, &Options[*corev1.ConfigMap]{Name: "test", CacheSyncTimeout: 200 * time.Millisecond})
+
+	err := ctrl.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "did not sync") {
+		t.Errorf("Run returned %v, want a cache sync timeout", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("Run did not return until the test timed out")
+	}
+	if ctrl.HasSynced() {
+		t.Error("expected HasSynced to be false")
+	}
+}
+
+func TestRunDrainsInFlightReconciles(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	started, release := make(chan struct{}), make(chan struct{})
+	var reconcileErr error
+	r := &deletionReconciler{}
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(ctx context.Context, cm *corev1.ConfigMap) error {
+		close(started)
+		<-release
+		reconcileErr = ctx.Err()
+		return r.Reconcile(ctx, cm)
+	}), &Options[*corev1.ConfigMap]{Name: "test", ShutdownGracePeriod: time.Minute})
+	ctrl.reconciler = struct {
+		Reconciler[*corev1.ConfigMap]
+		DeletionReconciler[*corev1.ConfigMap]
+	}{ctrl.reconciler, r}
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(runCtx) }()
+	<-started
+
+	// Queued while the reconcile is in flight, and never processed.
+	ctrl.queue.Add("default/queued")
+	stop()
+	select {
+	case err := <-done:
+		t.Fatalf("Run returned %v with a reconcile in flight", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	rec := httptest.NewRecorder()
+	ctrl.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/healthz while draining = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if s := ctrl.Status(); !s.Stopping || s.Ready() {
+		t.Errorf("expected a stopping, unready controller while draining, got %+v", s)
+	}
+
+	close(release)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for Run to return")
+	}
+	if reconcileErr != nil {
+		t.Errorf("in-flight reconcile's context error = %v, want nil", reconcileErr)
+	}
+	if reconciled, deleted := r.counts(); reconciled != 1 || deleted != 0 {
+		t.Errorf("expected only the in-flight reconcile to complete, got %d reconciles and %d deletions", reconciled, deleted)
+	}
+	if n := ctrl.Status().Workers; n != 0 {
+		t.Errorf("expected no running workers, got %d", n)
+	}
+}
+
+func TestStartWorkersAfterStop(t *testing.T) {
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, newConfigMapServer().config())
+	ctrl := New(client, &deletionReconciler{}, &Options[*corev1.ConfigMap]{Name: "test", Concurrency: 2})
+
+	ctrl.stopWorkers(context.Background())
+	ctrl.startWorkers(context.Background())
+	if n := ctrl.Status().Workers; n != 0 {
+		t.Errorf("expected no workers to start after stopWorkers, got %d", n)
+	}
+}
+
+func TestRunShutdownGracePeriod(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	started := make(chan struct{})
+	canceled := make(chan error, 1)
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(ctx context.Context, _ *corev1.ConfigMap) error {
+		close(started)
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return ctx.Err()
+	}), &Options[*corev1.ConfigMap]{Name: "test", ShutdownGracePeriod: 100 * time.Millisecond})
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(runCtx) }()
+	<-started
+
+	start := time.Now()
+	stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for Run to return")
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Run returned after %v, before the grace period", elapsed)
+	}
+	select {
+	case err := <-canceled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("in-flight reconcile's context error = %v, want Canceled", err)
+		}
+	default:
+		t.Error("Run returned before the in-flight reconcile")
+	}
+}
+
+// outcomeRecorder records reconcile outcomes. It must be used with a custom
+// Queue, as it provides no queue metrics.
+type outcomeRecorder struct {
+	workqueue.MetricsProvider
+	mu       sync.Mutex
+	outcomes []Outcome
+}
+
+func (m *outcomeRecorder) ObserveReconcile(_ string, outcome Outcome, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outcomes = append(m.outcomes, outcome)
+}
+
+func TestReconcilerPanic(t *testing.T) {
+	for _, permanent := range []bool{false, true} {
+		t.Run(fmt.Sprintf("permanent=%t", permanent), func(t *testing.T) {
+			client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, newConfigMapServer().config())
+			metrics := &outcomeRecorder{}
+			ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+				panic("boom")
+			}), &Options[*corev1.ConfigMap]{
+				Name:            "test",
+				Metrics:         metrics,
+				PermanentPanics: permanent,
+				Queue: workqueue.NewTypedRateLimitingQueue(
+					workqueue.NewTypedItemExponentialFailureRateLimiter[string](time.Hour, time.Hour)),
+			})
+			defer ctrl.shutdown()
+
+			ctrl.queue.Add("default/cm")
+			if !ctrl.processNextItem(context.Background()) {
+				t.Fatal("processNextItem returned false")
+			}
+
+			if len(metrics.outcomes) != 1 || metrics.outcomes[0] != OutcomePanic {
+				t.Errorf("expected a panic outcome, got %v", metrics.outcomes)
+			}
+			want := "reconciler panicked: boom"
+			if permanent {
+				want = "permanent error: " + want
+			}
+			// A dropped key keeps its last error.
+			items := ctrl.QueueItems()
+			if len(items) != 1 || items[0].LastError != want {
+				t.Fatalf("expected the last error to be %q, got queue items %+v", want, items)
+			}
+			if items[0].Pending == permanent {
+				t.Errorf("expected the key to be requeued only if panics aren't permanent, got %+v", items[0])
+			}
+		})
+	}
+}
+
+func TestReconcileTimeout(t *testing.T) {
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, newConfigMapServer().config())
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(ctx context.Context, _ *corev1.ConfigMap) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), &Options[*corev1.ConfigMap]{Name: "test", ReconcileTimeout: 50 * time.Millisecond})
+	defer ctrl.shutdown()
+
+	start := time.Now()
+	err := ctrl.processItem(context.Background(), "default/cm")
+	if !IsTimeoutError(err) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a timeout wrapping the reconciler's error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("reconcile took %v, expected it to time out after 50ms", elapsed)
+	}
+	if got := outcomeFor(err); got != OutcomeTimeout {
+		t.Errorf("outcome = %s, want %s", got, OutcomeTimeout)
+	}
+
+	// Errors from reconciles that finish in time are not timeouts. The first
+	// reconciler may have been abandoned, so a new controller is used.
+	reconcileErr := errors.New("boom")
+	ctrl = New(client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+		return reconcileErr
+	}), &Options[*corev1.ConfigMap]{Name: "test", ReconcileTimeout: 50 * time.Millisecond})
+	defer ctrl.shutdown()
+	if err := ctrl.processItem(context.Background(), "default/cm"); IsTimeoutError(err) || !errors.Is(err, reconcileErr) {
+		t.Errorf("expected the reconciler's error, got %v", err)
+	}
+}
+
+func TestReconcileTimeoutIgnoredContext(t *testing.T) {
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, newConfigMapServer().config())
+	release := make(chan struct{})
+	defer close(release)
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+		<-release
+		return nil
+	}), &Options[*corev1.ConfigMap]{Name: "test", ReconcileTimeout: 50 * time.Millisecond})
+	defer ctrl.shutdown()
+
+	start := time.Now()
+	err := ctrl.processItem(context.Background(), "default/cm")
+	if !IsTimeoutError(err) {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("reconcile took %v, expected it to be abandoned after 50ms", elapsed)
+	}
+}
+
+func TestRunAbandonsReconcilerIgnoringCancellation(t *testing.T) {
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, newConfigMapServer().config())
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+		close(started)
+		<-release
+		return nil
+	}), &Options[*corev1.ConfigMap]{Name: "test", ReconcileTimeout: 500 * time.Millisecond, ShutdownGracePeriod: 50 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(ctx) }()
+	<-started
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return while a reconciler ignored its canceled context")
+	}
+}
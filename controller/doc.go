@@ -36,6 +36,12 @@
 //	    return nil
 //	}
 //
+// Objects are read from the controller's informer cache. When a queued object
+// no longer exists, Reconcile is not called; implement DeletionReconciler to
+// be told about deletions instead. To clean up before an object is deleted,
+// implement Finalizer: the controller manages a finalizer on each object and
+// calls FinalizeKind once it is being deleted.
+//
 // # Automatic Updates
 //
 // The controller automatically persists changes made to the object during
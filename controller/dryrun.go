@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/chainguard-dev/clog"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+// logDryRun logs the change a dry-run write of the given kind ("metadata"
+// or "status") would have made, as a diff from before to the object returned
+// by the API server.
+func (c *Controller[T]) logDryRun(ctx context.Context, kind string, before, after T) {
+	meta := c.getObjectMeta(before)
+	if meta == nil {
+		return
+	}
+	clog.InfoContext(ctx, "dry run: would update "+kind,
+		"namespace", meta.Namespace,
+		"name", meta.Name,
+		"diff", diff.Diff(before, after))
+}
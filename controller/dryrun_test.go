@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/imjasonh/client-go2/generic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDryRun(t *testing.T) {
+	server := newConfigMapServer()
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(ctx context.Context, cm *corev1.ConfigMap) error {
+		cm.Annotations = map[string]string{"example.com/reconciled": "true"}
+		return nil
+	}), &Options[*corev1.ConfigMap]{DryRun: true})
+
+	var buf bytes.Buffer
+	ctx := clog.WithLogger(context.Background(), clog.New(slog.NewTextHandler(&buf, nil)))
+	if err := ctrl.processItem(ctx, "default/cm"); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+
+	if writes := server.writeLog(); len(writes) != 1 || writes[0].request != "PUT /api/v1/namespaces/default/configmaps/cm?dryRun=All" {
+		t.Errorf("expected a single dry-run update, got %v", writes)
+	}
+	logs := buf.String()
+	if !strings.Contains(logs, "dry run: would update metadata") {
+		t.Errorf("expected dry-run log, got %q", logs)
+	}
+	if !strings.Contains(logs, "example.com/reconciled") {
+		t.Errorf("expected diff to include the new annotation, got %q", logs)
+	}
+}
@@ -85,6 +85,36 @@ func IsRequeueError(err error) bool {
 		errors.Is(err, &requeueImmediately{})
 }
 
+// ErrReconcileTimeout is returned, wrapping the reconciler's own error, when
+// a reconcile does not finish within Options.ReconcileTimeout.
+var ErrReconcileTimeout = errors.New("reconcile timed out")
+
+// IsTimeoutError checks if an error is from a reconcile that timed out.
+func IsTimeoutError(err error) bool {
+	return errors.Is(err, ErrReconcileTimeout)
+}
+
+// panicError is returned in place of a panic recovered from a reconciler.
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (p *panicError) Error() string {
+	return fmt.Sprintf("reconciler panicked: %v", p.value)
+}
+
+// Is implements error matching for panicError
+func (p *panicError) Is(target error) bool {
+	_, ok := target.(*panicError)
+	return ok
+}
+
+// IsPanicError checks if an error is from a reconciler that panicked.
+func IsPanicError(err error) bool {
+	return errors.Is(err, &panicError{})
+}
+
 // GetRequeueDuration returns the requeue duration if the error indicates
 // a requeue after duration, otherwise returns 0.
 func GetRequeueDuration(err error) time.Duration {
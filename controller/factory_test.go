@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/imjasonh/client-go2/generic"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestWatchOwnedSharedInformer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newAPIServer()
+	server.set("/api/v1/namespaces/default/secrets/s", &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "owner", UID: "1"}}},
+	})
+	config := server.config()
+	factory := generic.NewInformerFactory()
+	podClient := generic.NewClientGVR[*corev1.Pod](schema.GroupVersionResource{Version: "v1", Resource: "pods"}, config)
+	secretClient := generic.NewClientGVR[*corev1.Secret](schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, config)
+
+	ctrl := New(podClient, ReconcilerFunc[*corev1.Pod](func(context.Context, *corev1.Pod) error { return nil }),
+		&Options[*corev1.Pod]{Namespace: "default", InformerFactory: factory})
+
+	lister, err := WatchOwned(ctx, ctrl, secretClient, false)
+	if err != nil {
+		t.Fatalf("WatchOwned failed: %v", err)
+	}
+	// Another consumer of the same Secrets shares the controller's informer.
+	secretClient.SharedInformer(factory, &generic.InformOptions[*corev1.Secret]{Namespace: "default"})
+
+	factory.Start(ctx)
+	if err := factory.WaitForCacheSync(ctx); err != nil {
+		t.Fatalf("WaitForCacheSync failed: %v", err)
+	}
+	if _, err := lister.ByNamespace("default").Get("s"); err != nil {
+		t.Errorf("expected owned secret in cache: %v", err)
+	}
+	for ctrl.queue.Len() == 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for owner to be enqueued")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if key, _ := ctrl.queue.Get(); key != "default/owner" {
+		t.Errorf("expected default/owner to be enqueued, got %q", key)
+	}
+
+	if got, want := server.requestLog(), []string{"GET /api/v1/namespaces/default/secrets"}; !slices.Equal(got, want) {
+		t.Errorf("expected a single list of secrets, got requests %v", got)
+	}
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/chainguard-dev/clog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+)
+
+// Finalizer is an optional interface for Reconcilers that need to clean up
+// before an object is deleted.
+//
+// If a Reconciler implements Finalizer, the controller adds its finalizer
+// (Options.FinalizerName) to each object before the first Reconcile. Once the
+// object is being deleted, FinalizeKind is called instead of Reconcile, and
+// the finalizer is removed only when FinalizeKind succeeds, letting the
+// deletion proceed. Errors are retried like errors from Reconcile.
+//
+// As with Reconcile, changes FinalizeKind makes to the object's status are
+// persisted.
+type Finalizer[T runtime.Object] interface {
+	FinalizeKind(ctx context.Context, obj T) error
+}
+
+// defaultFinalizerName returns the finalizer of a controller named name for
+// resource gr: the resource and group, qualified with the controller's name
+// so that controllers of the same resource don't share a finalizer, e.g.
+// "widgets.example.com/widget-controller". Core resources use the group
+// "core", e.g. "pods.core/pod-controller".
+func defaultFinalizerName(gr schema.GroupResource, name string) string {
+	if gr.Group == "" {
+		gr.Group = "core"
+	}
+	// The name part of a finalizer is at most 63 alphanumerics, '-', '_'
+	// or '.', beginning and ending with an alphanumeric.
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', '0' <= r && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case 'A' <= r && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, name)
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	name = strings.Trim(name, "-_.")
+	if name == "" {
+		name = "finalizer"
+	}
+	return gr.String() + "/" + name
+}
+
+// ensureFinalizer adds the controller's finalizer to current and persists it,
+// if it is not already present. It returns the object to compare the
+// reconciler's changes against.
+func (c *Controller[T]) ensureFinalizer(ctx context.Context, original, current T) (T, error) {
+	meta := c.getObjectMeta(current)
+	if meta == nil {
+		return original, fmt.Errorf("failed to get object metadata")
+	}
+	if slices.Contains(meta.Finalizers, c.finalizer) {
+		return original, nil
+	}
+	meta.Finalizers = append(meta.Finalizers, c.finalizer)
+	clog.DebugContext(ctx, "adding finalizer", "namespace", meta.Namespace, "name", meta.Name, "finalizer", c.finalizer)
+	if err := c.updateFinalizerWithRetry(ctx, current, true); err != nil {
+		return original, fmt.Errorf("failed to add finalizer: %w", err)
+	}
+	return c.deepCopy(current), nil
+}
+
+// finalize calls FinalizeKind for an object that is being deleted and, if it
+// succeeds, removes the controller's finalizer.
+func (c *Controller[T]) finalize(ctx context.Context, key string, f Finalizer[T], original, current T) error {
+	meta := c.getObjectMeta(current)
+	if !slices.Contains(meta.Finalizers, c.finalizer) {
+		// Already finalized; the object is waiting on other finalizers.
+		return nil
+	}
+	if err := c.invoke(ctx, key, func(ctx context.Context) error {
+		return f.FinalizeKind(ctx, current)
+	}); err != nil {
+		return err
+	}
+
+	// Persist status first: once the last finalizer is removed the object
+	// may be gone.
+	if !c.equalStatus(original, current) {
+		if err := c.updateStatusWithRetry(ctx, original, current); err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+	}
+	meta = c.getObjectMeta(current)
+	meta.Finalizers = slices.DeleteFunc(meta.Finalizers, func(name string) bool { return name == c.finalizer })
+	clog.DebugContext(ctx, "removing finalizer", "namespace", meta.Namespace, "name", meta.Name, "finalizer", c.finalizer)
+	if err := c.updateFinalizerWithRetry(ctx, current, false); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return nil
+}
+
+// updateFinalizerWithRetry adds the controller's finalizer to, or removes it
+// from, the latest version of obj, with conflict retry. Only the
+// controller's own finalizer is changed, so finalizers other controllers
+// added since obj was read are kept.
+func (c *Controller[T]) updateFinalizerWithRetry(ctx context.Context, obj T, add bool) error {
+	meta := c.getObjectMeta(obj)
+	if meta == nil {
+		return fmt.Errorf("no metadata")
+	}
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest, err := c.client.Get(ctx, meta.Namespace, meta.Name, nil)
+		if err != nil {
+			return err
+		}
+		before := c.deepCopy(latest)
+
+		latestMeta := c.getObjectMeta(latest)
+		if latestMeta == nil {
+			return fmt.Errorf("no metadata in latest")
+		}
+		if slices.Contains(latestMeta.Finalizers, c.finalizer) == add {
+			return nil
+		}
+		if add {
+			latestMeta.Finalizers = append(latestMeta.Finalizers, c.finalizer)
+		} else {
+			latestMeta.Finalizers = slices.DeleteFunc(latestMeta.Finalizers, func(name string) bool { return name == c.finalizer })
+		}
+
+		updated, err := c.client.Update(ctx, meta.Namespace, latest, nil)
+		if err == nil && c.dryRun {
+			c.logDryRun(ctx, "metadata", before, updated)
+		}
+		return err
+	})
+}
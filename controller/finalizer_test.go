@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/imjasonh/client-go2/generic"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+)
+
+const podPath = "/api/v1/namespaces/default/pods/p"
+
+// finalizerWrites returns the finalizers of each write to default/p.
+func finalizerWrites(s *apiServer) [][]string {
+	var finalizers [][]string
+	for _, w := range s.writeLog() {
+		var pod corev1.Pod
+		if err := json.Unmarshal(w.object, &pod); err == nil && pod.Name == "p" {
+			finalizers = append(finalizers, pod.Finalizers)
+		}
+	}
+	return finalizers
+}
+
+// finalizingReconciler records calls to Reconcile and FinalizeKind.
+type finalizingReconciler struct {
+	reconciled, finalized int
+	finalizeErr           error
+}
+
+func (r *finalizingReconciler) Reconcile(context.Context, *corev1.Pod) error {
+	r.reconciled++
+	return nil
+}
+
+func (r *finalizingReconciler) FinalizeKind(_ context.Context, pod *corev1.Pod) error {
+	r.finalized++
+	pod.Status.Message = "finalized"
+	return r.finalizeErr
+}
+
+func TestFinalizer(t *testing.T) {
+	ctx := context.Background()
+	server := newAPIServer()
+	server.set(podPath, &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "default", Finalizers: []string{"other"}},
+	})
+	client := generic.NewClientGVR[*corev1.Pod](schema.GroupVersionResource{Version: "v1", Resource: "pods"}, server.config())
+	r := &finalizingReconciler{}
+	ctrl := New(client, r, &Options[*corev1.Pod]{FinalizerName: "example.com/cleanup"})
+
+	// The finalizer is added before the first Reconcile.
+	if err := ctrl.processItem(ctx, "default/p"); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+	if r.reconciled != 1 || r.finalized != 0 {
+		t.Errorf("got %d reconciles and %d finalizes, want 1 and 0", r.reconciled, r.finalized)
+	}
+	if got, want := finalizerWrites(server), [][]string{{"other", "example.com/cleanup"}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("writes = %v, want %v", got, want)
+	}
+
+	// It isn't added again.
+	if err := ctrl.processItem(ctx, "default/p"); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+	if got := finalizerWrites(server); len(got) != 1 {
+		t.Errorf("expected no more writes, got %v", got)
+	}
+
+	// Once the object is deleted, FinalizeKind is called instead of Reconcile.
+	// The finalizer stays while it fails.
+	var pod corev1.Pod
+	server.get(podPath, &pod)
+	now := metav1.Now()
+	pod.DeletionTimestamp = &now
+	server.set(podPath, &pod)
+	r.finalizeErr = errors.New("cleanup failed")
+	if err := ctrl.processItem(ctx, "default/p"); !errors.Is(err, r.finalizeErr) {
+		t.Fatalf("processItem returned %v, want %v", err, r.finalizeErr)
+	}
+	if r.reconciled != 2 || r.finalized != 1 {
+		t.Errorf("got %d reconciles and %d finalizes, want 2 and 1", r.reconciled, r.finalized)
+	}
+	if got := finalizerWrites(server); len(got) != 1 {
+		t.Errorf("expected the finalizer to remain after FinalizeKind failed, got writes %v", got)
+	}
+
+	// When it succeeds, status is persisted and the finalizer is removed.
+	r.finalizeErr = nil
+	if err := ctrl.processItem(ctx, "default/p"); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+	if got, want := finalizerWrites(server), [][]string{{"other", "example.com/cleanup"}, {"other", "example.com/cleanup"}, {"other"}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("writes = %v, want %v", got, want)
+	}
+	server.get(podPath, &pod)
+	if pod.Status.Message != "finalized" {
+		t.Errorf("expected FinalizeKind's status change to be persisted, got %q", pod.Status.Message)
+	}
+
+	// Nothing more happens while other finalizers are pending.
+	if err := ctrl.processItem(ctx, "default/p"); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+	if got := finalizerWrites(server); r.finalized != 2 || len(got) != 3 {
+		t.Errorf("expected no more finalizes or writes, got %d finalizes and writes %v", r.finalized, got)
+	}
+}
+
+func TestFinalizerKeepsOtherFinalizers(t *testing.T) {
+	ctx := context.Background()
+	server := newAPIServer()
+	setFinalizers := func(deleting bool, finalizers ...string) *corev1.Pod {
+		t.Helper()
+		var pod corev1.Pod
+		if !server.get(podPath, &pod) {
+			pod = corev1.Pod{
+				TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "default"},
+			}
+		}
+		pod.Finalizers = finalizers
+		if deleting {
+			now := metav1.Now()
+			pod.DeletionTimestamp = &now
+		}
+		server.set(podPath, &pod)
+		server.get(podPath, &pod)
+		return &pod
+	}
+	client := generic.NewClientGVR[*corev1.Pod](schema.GroupVersionResource{Version: "v1", Resource: "pods"}, server.config())
+	r := &finalizingReconciler{}
+	ctrl := New(client, r, &Options[*corev1.Pod]{FinalizerName: "example.com/cleanup"})
+
+	// Another finalizer is added after the controller read the object.
+	stale := setFinalizers(false, "other")
+	setFinalizers(false, "other", "other2")
+	if _, err := ctrl.ensureFinalizer(ctx, stale.DeepCopy(), stale.DeepCopy()); err != nil {
+		t.Fatalf("ensureFinalizer failed: %v", err)
+	}
+	var pod corev1.Pod
+	server.get(podPath, &pod)
+	if want := []string{"other", "other2", "example.com/cleanup"}; !slices.Equal(pod.Finalizers, want) {
+		t.Errorf("finalizers after adding = %v, want %v", pod.Finalizers, want)
+	}
+
+	stale = setFinalizers(true, "other", "example.com/cleanup")
+	setFinalizers(true, "other", "example.com/cleanup", "other3")
+	if err := ctrl.finalize(ctx, "default/p", r, stale.DeepCopy(), stale.DeepCopy()); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	server.get(podPath, &pod)
+	if want := []string{"other", "other3"}; !slices.Equal(pod.Finalizers, want) {
+		t.Errorf("finalizers after removing = %v, want %v", pod.Finalizers, want)
+	}
+}
+
+func TestFinalizerDefaultName(t *testing.T) {
+	for _, tt := range []struct {
+		gvr  schema.GroupVersionResource
+		name string
+		want string
+	}{
+		{schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}, "widget-controller", "widgets.example.com/widget-controller"},
+		{schema.GroupVersionResource{Version: "v1", Resource: "pods"}, "pod-controller", "pods.core/pod-controller"},
+		{schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}, "", "widgets.example.com/widget.example.com"},
+		{schema.GroupVersionResource{Version: "v1", Resource: "pods"}, "Pod Cleaner!", "pods.core/pod-cleaner"},
+		{schema.GroupVersionResource{Version: "v1", Resource: "pods"}, strings.Repeat("x", 100), "pods.core/" + strings.Repeat("x", 63)},
+	} {
+		client := generic.NewClientGVR[*corev1.Pod](tt.gvr, &rest.Config{Host: "http://localhost"})
+		ctrl := New(client, &finalizingReconciler{}, &Options[*corev1.Pod]{Name: tt.name})
+		if ctrl.finalizer != tt.want {
+			t.Errorf("default finalizer of %q = %q, want %q", tt.name, ctrl.finalizer, tt.want)
+		}
+		if errs := validation.IsQualifiedName(ctrl.finalizer); len(errs) != 0 {
+			t.Errorf("default finalizer %q is not a qualified name: %v", ctrl.finalizer, errs)
+		}
+		ctrl.shutdown()
+	}
+}
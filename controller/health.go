@@ -0,0 +1,333 @@
+package controller
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// ControllerStatus is the state of a controller, as reported by its
+// Status method and served by its Handler.
+type ControllerStatus struct {
+	// Name is the controller's name.
+	Name string `json:"name"`
+	// Synced is true once the controller's caches have synced.
+	Synced bool `json:"synced"`
+	// LeaderElection is true if the controller only runs its workers while
+	// it is the elected leader.
+	LeaderElection bool `json:"leaderElection"`
+	// Leader is true while the controller's workers run as the leader.
+	Leader bool `json:"leader"`
+	// Starting is true once the controller should run its workers, having
+	// synced and, with leader election, been elected, until they start.
+	Starting bool `json:"starting"`
+	// Workers is the number of workers currently running.
+	Workers int `json:"workers"`
+	// Stopping is true once the controller has begun shutting down, while
+	// its workers finish in-flight reconciles and exit.
+	Stopping bool `json:"stopping"`
+}
+
+// Ready returns true if the controller has synced, is not stopping and,
+// unless it is a follower waiting to be elected, its workers are running.
+func (s ControllerStatus) Ready() bool {
+	return s.Synced && !s.Starting && !s.Stopping && s.Healthy()
+}
+
+// Healthy returns false if the controller should be running workers but
+// none are running, which it does not recover from. A starting controller
+// is healthy until its workers start, and a stopping one while they drain.
+func (s ControllerStatus) Healthy() bool {
+	if !s.Synced || s.Starting || s.Stopping || (s.LeaderElection && !s.Leader) {
+		return true
+	}
+	return s.Workers > 0
+}
+
+// Status returns the controller's current state.
+func (c *Controller[T]) Status() ControllerStatus {
+	s := ControllerStatus{
+		Name:           c.name,
+		Synced:         c.HasSynced(),
+		LeaderElection: c.elected,
+		Leader:         c.IsLeader(),
+		Workers:        int(c.workers.Load()),
+		Stopping:       c.stopping.Load(),
+	}
+	s.Starting = s.Synced && (!s.LeaderElection || s.Leader) && !c.started.Load()
+	return s
+}
+
+// QueueItem describes a key known to a controller's workqueue: one waiting
+// to be reconciled, or whose last reconcile failed.
+type QueueItem struct {
+	// Key is the namespace/name key of the object.
+	Key string `json:"key"`
+	// Pending is true if the key is waiting to be reconciled, including
+	// while it backs off after an error.
+	Pending bool `json:"pending"`
+	// Requeues is how many times the key has been requeued with backoff
+	// since it last reconciled successfully.
+	Requeues int `json:"requeues"`
+	// LastError is the error from the key's last reconcile, if it failed.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is when the last reconcile failed.
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// QueueItems returns the keys waiting in the controller's queue and those
+// whose last reconcile failed, sorted by key.
+func (c *Controller[T]) QueueItems() []QueueItem {
+	if c.tracker == nil {
+		return nil
+	}
+	return c.tracker.items()
+}
+
+// trackingQueue wraps a workqueue to record which keys are waiting in it and
+// the last error each key's reconcile returned, neither of which a workqueue
+// exposes. A key's error is kept until it reconciles successfully or its
+// object is deleted, so the errors of keys dropped after a permanent error
+// or too many retries stay visible, for up to errorRetention. Nothing is
+// pending once the queue shuts down.
+type trackingQueue struct {
+	workqueue.TypedRateLimitingInterface[string]
+
+	mu       sync.Mutex
+	shutDown bool
+	pending  map[string]struct{}
+	errors   map[string]keyError
+}
+
+// errorRetention is how long the last error of a key that is no longer
+// being reconciled is kept.
+const errorRetention = time.Hour
+
+// keyError is the last reconcile error of a key.
+type keyError struct {
+	err  string
+	time time.Time
+}
+
+func newTrackingQueue(queue workqueue.TypedRateLimitingInterface[string]) *trackingQueue {
+	return &trackingQueue{
+		TypedRateLimitingInterface: queue,
+		pending:                    map[string]struct{}{},
+		errors:                     map[string]keyError{},
+	}
+}
+
+// add records that key is pending and adds it to the queue with add. The key
+// is recorded before it is added, so it is always recorded before a worker
+// can get it. Keys are not added once the queue is shut down.
+func (q *trackingQueue) add(key string, add func(string)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shutDown {
+		return
+	}
+	q.pending[key] = struct{}{}
+	add(key)
+}
+
+func (q *trackingQueue) Add(key string) {
+	q.add(key, q.TypedRateLimitingInterface.Add)
+}
+
+func (q *trackingQueue) AddAfter(key string, duration time.Duration) {
+	q.add(key, func(key string) { q.TypedRateLimitingInterface.AddAfter(key, duration) })
+}
+
+func (q *trackingQueue) AddRateLimited(key string) {
+	q.add(key, q.TypedRateLimitingInterface.AddRateLimited)
+}
+
+func (q *trackingQueue) Get() (string, bool) {
+	key, quit := q.TypedRateLimitingInterface.Get()
+	if !quit {
+		q.mu.Lock()
+		delete(q.pending, key)
+		q.mu.Unlock()
+	}
+	return key, quit
+}
+
+func (q *trackingQueue) ShutDown() {
+	q.stop()
+	q.TypedRateLimitingInterface.ShutDown()
+}
+
+func (q *trackingQueue) ShutDownWithDrain() {
+	q.stop()
+	q.TypedRateLimitingInterface.ShutDownWithDrain()
+}
+
+// stop clears the pending keys, which a shut down queue never processes,
+// and stops recording new ones.
+func (q *trackingQueue) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shutDown = true
+	clear(q.pending)
+}
+
+// observe records the result of reconciling key. Requests to requeue are
+// not errors. It is safe to call on a nil queue.
+func (q *trackingQueue) observe(key string, err error) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if outcome := outcomeFor(err); outcome == OutcomeSuccess || outcome == OutcomeRequeue {
+		delete(q.errors, key)
+		return
+	}
+	q.expire()
+	q.errors[key] = keyError{err: err.Error(), time: time.Now()}
+}
+
+// deleted clears the last error of key, whose object was deleted. It is safe
+// to call on a nil queue.
+func (q *trackingQueue) deleted(key string) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.errors, key)
+}
+
+// expire clears the errors of keys that are not pending and last failed
+// more than errorRetention ago. A key being retried fails again, refreshing
+// its error, well within errorRetention. q.mu must be held.
+func (q *trackingQueue) expire() {
+	cutoff := time.Now().Add(-errorRetention)
+	for key, e := range q.errors {
+		if _, ok := q.pending[key]; !ok && e.time.Before(cutoff) {
+			delete(q.errors, key)
+		}
+	}
+}
+
+// items returns the pending and failed keys, sorted by key.
+func (q *trackingQueue) items() []QueueItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expire()
+	byKey := make(map[string]*QueueItem, len(q.pending)+len(q.errors))
+	item := func(key string) *QueueItem {
+		if it, ok := byKey[key]; ok {
+			return it
+		}
+		it := &QueueItem{Key: key, Requeues: q.NumRequeues(key)}
+		byKey[key] = it
+		return it
+	}
+	for key := range q.pending {
+		item(key).Pending = true
+	}
+	for key, e := range q.errors {
+		it := item(key)
+		it.LastError = e.err
+		it.LastErrorTime = &e.time
+	}
+	items := make([]QueueItem, 0, len(byKey))
+	for _, it := range byKey {
+		items = append(items, *it)
+	}
+	slices.SortFunc(items, func(a, b QueueItem) int { return cmp.Compare(a.Key, b.Key) })
+	return items
+}
+
+// inspectable is a controller whose state can be served by a health handler.
+type inspectable interface {
+	Status() ControllerStatus
+	QueueItems() []QueueItem
+}
+
+// Handler returns an http.Handler serving the controller's health:
+//
+//   - /healthz fails if the controller should be running workers but isn't.
+//   - /readyz fails until the controller has synced its caches and, unless
+//     it is a follower waiting to be elected, started its workers, and
+//     fails again once it starts stopping.
+//   - /debug/queue lists the keys in the controller's queue and the last
+//     error of each key that failed, as JSON.
+//
+// It can be served with http.ListenAndServe, or mounted on an existing mux.
+func (c *Controller[T]) Handler() http.Handler {
+	return healthHandler(func() []inspectable { return []inspectable{c} })
+}
+
+// healthHandler returns a handler serving the health of controllers.
+func healthHandler(controllers func() []inspectable) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeChecks(w, controllers(), ControllerStatus.Healthy, "healthz")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, _ *http.Request) {
+		writeChecks(w, controllers(), ControllerStatus.Ready, "readyz")
+	})
+	mux.HandleFunc("GET /debug/queue", func(w http.ResponseWriter, _ *http.Request) {
+		type queue struct {
+			ControllerStatus
+			Items []QueueItem `json:"items"`
+		}
+		queues := []queue{}
+		for _, c := range controllers() {
+			queues = append(queues, queue{ControllerStatus: c.Status(), Items: c.QueueItems()})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(queues)
+	})
+	return mux
+}
+
+// writeChecks writes the result of check for each controller, in the style
+// of the Kubernetes API server's verbose health checks, failing with 503 if
+// any controller fails it.
+func writeChecks(w http.ResponseWriter, controllers []inspectable, check func(ControllerStatus) bool, name string) {
+	var b strings.Builder
+	status := http.StatusOK
+	for _, c := range controllers {
+		s := c.Status()
+		if check(s) {
+			fmt.Fprintf(&b, "[+]%s ok\n", s.Name)
+			continue
+		}
+		status = http.StatusServiceUnavailable
+		fmt.Fprintf(&b, "[-]%s failed: %s\n", s.Name, reason(s))
+	}
+	if status == http.StatusOK {
+		fmt.Fprintf(&b, "%s check passed\n", name)
+	} else {
+		fmt.Fprintf(&b, "%s check failed\n", name)
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// reason describes why a controller is not ready.
+func reason(s ControllerStatus) string {
+	switch {
+	case !s.Synced:
+		return "caches not synced"
+	case s.Starting:
+		return "workers starting"
+	case s.Stopping:
+		return "stopping"
+	}
+	return "workers not running"
+}
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imjasonh/client-go2/generic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestControllerStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		status         ControllerStatus
+		ready, healthy bool
+	}{
+		{"not started", ControllerStatus{}, false, true},
+		{"running", ControllerStatus{Synced: true, Workers: 2}, true, true},
+		{"workers exited", ControllerStatus{Synced: true}, false, false},
+		{"starting", ControllerStatus{Synced: true, Starting: true}, false, true},
+		{"follower", ControllerStatus{Synced: true, LeaderElection: true}, true, true},
+		{"leader starting", ControllerStatus{Synced: true, LeaderElection: true, Leader: true, Starting: true}, false, true},
+		{"leader", ControllerStatus{Synced: true, LeaderElection: true, Leader: true, Workers: 1}, true, true},
+		{"leader without workers", ControllerStatus{Synced: true, LeaderElection: true, Leader: true}, false, false},
+		{"stopping", ControllerStatus{Synced: true, Workers: 1, Stopping: true}, false, true},
+		{"drained", ControllerStatus{Synced: true, Stopping: true}, false, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.Ready(); got != tt.ready {
+				t.Errorf("Ready() = %t, want %t", got, tt.ready)
+			}
+			if got := tt.status.Healthy(); got != tt.healthy {
+				t.Errorf("Healthy() = %t, want %t", got, tt.healthy)
+			}
+		})
+	}
+}
+
+func TestStatusStarting(t *testing.T) {
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, newConfigMapServer().config())
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+		return nil
+	}), &Options[*corev1.ConfigMap]{Name: "test"})
+
+	// Between syncing and starting its workers, the controller is healthy but
+	// not ready.
+	ctrl.setSynced(true)
+	if s := ctrl.Status(); !s.Starting || !s.Healthy() || s.Ready() {
+		t.Errorf("expected a starting controller to be healthy but not ready, got %+v", s)
+	}
+	ctrl.startWorkers(context.Background())
+	defer ctrl.stopWorkers(context.Background())
+	if s := ctrl.Status(); s.Starting || !s.Healthy() || !s.Ready() {
+		t.Errorf("expected a running controller to be healthy and ready, got %+v", s)
+	}
+}
+
+func TestTrackingQueue(t *testing.T) {
+	q := newTrackingQueue(workqueue.NewTypedRateLimitingQueue(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[string](time.Hour, time.Hour)))
+	defer q.ShutDown()
+
+	q.Add("default/a")
+	q.Add("default/b")
+	key, _ := q.Get()
+	q.observe(key, errors.New("boom"))
+	q.AddRateLimited(key)
+	q.Done(key)
+	q.observe("default/c", PermanentError(errors.New("bad")))
+	q.observe("default/d", RequeueAfter(time.Minute))
+
+	items := q.items()
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %+v", items)
+	}
+	if it := items[0]; it.Key != "default/a" || !it.Pending || it.Requeues != 1 || it.LastError != "boom" || it.LastErrorTime == nil {
+		t.Errorf("unexpected item for the failed key: %+v", it)
+	}
+	if it := items[1]; it.Key != "default/b" || !it.Pending || it.Requeues != 0 || it.LastError != "" {
+		t.Errorf("unexpected item for the queued key: %+v", it)
+	}
+	if it := items[2]; it.Key != "default/c" || it.Pending || it.LastError == "" {
+		t.Errorf("unexpected item for the dropped key: %+v", it)
+	}
+
+	q.observe("default/a", nil)
+	if items := q.items(); items[0].LastError != "" {
+		t.Errorf("expected a successful reconcile to clear the error, got %+v", items[0])
+	}
+
+	// Dropping a key keeps its error, until its object is deleted.
+	q.Forget("default/c")
+	if items := q.items(); len(items) != 3 || items[2].Key != "default/c" || items[2].LastError == "" {
+		t.Errorf("expected a dropped key to keep its error, got %+v", items)
+	}
+	q.deleted("default/c")
+	if items := q.items(); len(items) != 2 || items[0].Key != "default/a" || items[1].Key != "default/b" {
+		t.Errorf("expected deleting a key's object to clear its error, got %+v", items)
+	}
+
+	// The errors of keys that are no longer reconciled expire, while those of
+	// pending keys are kept.
+	q.observe("default/c", PermanentError(errors.New("bad")))
+	q.observe("default/b", errors.New("boom"))
+	q.mu.Lock()
+	for key, e := range q.errors {
+		e.time = e.time.Add(-errorRetention - time.Second)
+		q.errors[key] = e
+	}
+	q.mu.Unlock()
+	if items := q.items(); len(items) != 2 || items[0].Key != "default/a" || items[1].Key != "default/b" || items[1].LastError != "boom" {
+		t.Errorf("expected only the dropped key's error to expire, got %+v", items)
+	}
+
+	q.ShutDown()
+	q.Add("default/e")
+	if items := q.items(); len(items) != 0 {
+		t.Errorf("expected no pending keys after shutdown, got %+v", items)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+		return errors.New("boom")
+	}), &Options[*corev1.ConfigMap]{
+		Name: "test",
+		// Back off for long enough that the failed key stays queued.
+		Queue: workqueue.NewTypedRateLimitingQueue(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[string](time.Hour, time.Hour)),
+	})
+	srv := httptest.NewServer(ctrl.Handler())
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get("/readyz"); code != http.StatusServiceUnavailable || !strings.Contains(body, "[-]test failed: caches not synced") {
+		t.Errorf("/readyz before Run = %d %q", code, body)
+	}
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz before Run = %d", code)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(ctx) }()
+	waitUntil(t, ctx, "the reconcile to fail", func() bool {
+		items := ctrl.QueueItems()
+		return len(items) == 1 && items[0].LastError != ""
+	})
+
+	if code, body := get("/readyz"); code != http.StatusOK || !strings.Contains(body, "[+]test ok") {
+		t.Errorf("/readyz = %d %q", code, body)
+	}
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz = %d", code)
+	}
+
+	code, body := get("/debug/queue")
+	if code != http.StatusOK {
+		t.Fatalf("/debug/queue = %d %q", code, body)
+	}
+	var queues []struct {
+		ControllerStatus
+		Items []QueueItem `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(body), &queues); err != nil {
+		t.Fatalf("decoding /debug/queue failed: %v\n%s", err, body)
+	}
+	if len(queues) != 1 || queues[0].Name != "test" || queues[0].Workers != 1 || len(queues[0].Items) != 1 {
+		t.Fatalf("unexpected /debug/queue response: %s", body)
+	}
+	if it := queues[0].Items[0]; it.Key != "default/cm" || !it.Pending || it.Requeues != 1 || it.LastError != "boom" {
+		t.Errorf("unexpected queue item: %+v", it)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+}
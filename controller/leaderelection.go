@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/imjasonh/client-go2/generic"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// ErrLeadershipLost is returned from Run when a controller using leader
+// election loses its lease.
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// Default leader election durations, matching those of kube-controller-manager.
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+)
+
+// LeaderElection configures leader election for a Controller, so that only
+// one of several replicas reconciles at a time.
+//
+// Every replica syncs its caches, but only the holder of the Lease starts
+// workers. If the leader fails to renew its lease, the context passed to its
+// in-flight reconciles is canceled, OnStoppedLeading is called, and Run
+// returns ErrLeadershipLost; the process should exit and restart as a
+// follower. When Run's context is canceled the workers are stopped
+// gracefully and then the lease is released, so that another replica can
+// take over without waiting for it to expire, but not while reconciles are
+// still in flight.
+type LeaderElection struct {
+	// Client reads and writes the Lease. Required.
+	Client generic.Client[*coordinationv1.Lease]
+
+	// LeaseName and LeaseNamespace identify the Lease. Required.
+	LeaseName      string
+	LeaseNamespace string
+
+	// Identity uniquely identifies this replica as a lease holder.
+	// Defaults to the hostname followed by a random suffix.
+	Identity string
+
+	// LeaseDuration is how long followers wait after the last renewal
+	// before trying to take over the lease. Defaults to 15 seconds.
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the leader keeps trying to renew its lease
+	// before giving up leadership. Defaults to 10 seconds.
+	RenewDeadline time.Duration
+	// RetryPeriod is how often to try to acquire or renew the lease.
+	// Defaults to 2 seconds.
+	RetryPeriod time.Duration
+
+	// OnStartedLeading, if set, is called when this replica becomes the
+	// leader, with a context that is canceled when it stops leading.
+	OnStartedLeading func(ctx context.Context)
+	// OnStoppedLeading, if set, is called when this replica stops leading,
+	// whether because it lost its lease or because Run's context was canceled.
+	OnStoppedLeading func()
+}
+
+// elector returns a LeaderElector for le, with callbacks that wrap those in
+// le.
+func (le *LeaderElection) elector(name string, callbacks leaderelection.LeaderCallbacks) (*leaderelection.LeaderElector, error) {
+	if le.LeaseName == "" || le.LeaseNamespace == "" {
+		return nil, errors.New("leader election requires a lease name and namespace")
+	}
+	identity := le.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname for leader election identity: %w", err)
+		}
+		identity = hostname + "_" + string(uuid.NewUUID())
+	}
+	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &leaseLock{
+			client:    le.Client,
+			namespace: le.LeaseNamespace,
+			name:      le.LeaseName,
+			identity:  identity,
+		},
+		LeaseDuration:   cmp.Or(le.LeaseDuration, defaultLeaseDuration),
+		RenewDeadline:   cmp.Or(le.RenewDeadline, defaultRenewDeadline),
+		RetryPeriod:     cmp.Or(le.RetryPeriod, defaultRetryPeriod),
+		Callbacks:       callbacks,
+		ReleaseOnCancel: true,
+		Name:            name,
+	})
+}
+
+// run waits to be elected leader, then calls lead with a context that is
+// canceled when leadership ends. It runs until ctx is done, returning nil,
+// or until leadership is lost, returning ErrLeadershipLost. setLeading is
+// called as leadership is gained and lost.
+func (le *LeaderElection) run(ctx context.Context, name string, setLeading func(bool), lead func(context.Context)) error {
+	var leading atomic.Bool
+	elector, err := le.elector(name, leaderelection.LeaderCallbacks{
+		OnStartedLeading: func(leaderCtx context.Context) {
+			clog.InfoContext(ctx, "started leading", "name", name)
+			leading.Store(true)
+			setLeading(true)
+			if le.OnStartedLeading != nil {
+				le.OnStartedLeading(leaderCtx)
+			}
+			lead(leaderCtx)
+		},
+		OnStoppedLeading: func() {
+			if !leading.Swap(false) {
+				return // Never became the leader.
+			}
+			setLeading(false)
+			clog.InfoContext(ctx, "stopped leading", "name", name)
+			if le.OnStoppedLeading != nil {
+				le.OnStoppedLeading()
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	clog.InfoContext(ctx, "waiting for leadership", "name", name, "lease", le.LeaseNamespace+"/"+le.LeaseName)
+	// Run returns once ctx is done or the lease could not be renewed, and
+	// cancels the context it passed to lead either way.
+	elector.Run(ctx)
+	if ctx.Err() == nil {
+		return fmt.Errorf("%s: %w", name, ErrLeadershipLost)
+	}
+	return nil
+}
+
+// IsLeader returns true if the controller is currently running its workers
+// as the elected leader. It is always false without leader election.
+func (c *Controller[T]) IsLeader() bool {
+	return c.leading.Load()
+}
+
+// leaseLock implements resourcelock.Interface with a generic client.
+type leaseLock struct {
+	client    generic.Client[*coordinationv1.Lease]
+	namespace string
+	name      string
+	identity  string
+	// lease is the last version of the Lease read or written.
+	lease *coordinationv1.Lease
+}
+
+var _ resourcelock.Interface = (*leaseLock)(nil)
+
+// Get implements resourcelock.Interface.
+func (l *leaseLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	lease, err := l.client.Get(ctx, l.namespace, l.name, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	l.lease = lease
+	record := resourcelock.LeaseSpecToLeaderElectionRecord(&lease.Spec)
+	raw, err := json.Marshal(*record)
+	if err != nil {
+		return nil, nil, err
+	}
+	return record, raw, nil
+}
+
+// Create implements resourcelock.Interface.
+func (l *leaseLock) Create(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	lease, err := l.client.Create(ctx, l.namespace, &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: l.name, Namespace: l.namespace},
+		Spec:       resourcelock.LeaderElectionRecordToLeaseSpec(&ler),
+	}, nil)
+	if err != nil {
+		return err
+	}
+	l.lease = lease
+	return nil
+}
+
+// Update implements resourcelock.Interface.
+func (l *leaseLock) Update(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if l.lease == nil {
+		return errors.New("lease not initialized, call get or create first")
+	}
+	lease := l.lease.DeepCopy()
+	lease.Spec = resourcelock.LeaderElectionRecordToLeaseSpec(&ler)
+	updated, err := l.client.Update(ctx, l.namespace, lease, nil)
+	if err != nil {
+		return err
+	}
+	l.lease = updated
+	return nil
+}
+
+// RecordEvent implements resourcelock.Interface. Events are not recorded.
+func (l *leaseLock) RecordEvent(string) {}
+
+// Identity implements resourcelock.Interface.
+func (l *leaseLock) Identity() string {
+	return l.identity
+}
+
+// Describe implements resourcelock.Interface.
+func (l *leaseLock) Describe() string {
+	return l.namespace + "/" + l.name
+}
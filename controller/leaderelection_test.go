@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imjasonh/client-go2/generic"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// leaseHolder returns the holder of the test-controller Lease in store.
+func leaseHolder(store *apiServer) string {
+	lease, err := generic.NewClientGVR[*coordinationv1.Lease](leasesGVR, store.config()).Get(context.Background(), "kube-system", "test-controller", nil)
+	if err != nil || lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+// newElectedController returns a controller for default/cm that elects a
+// leader with store, and counts its reconciles.
+func newElectedController(store *apiServer, identity string, reconcile func(context.Context) error) *Controller[*corev1.ConfigMap] {
+	server := newConfigMapServer()
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	leaseClient := generic.NewClientGVR[*coordinationv1.Lease](leasesGVR, store.config())
+	return New(client, ReconcilerFunc[*corev1.ConfigMap](func(ctx context.Context, _ *corev1.ConfigMap) error {
+		return reconcile(ctx)
+	}), &Options[*corev1.ConfigMap]{
+		Name: identity,
+		LeaderElection: &LeaderElection{
+			Client:         leaseClient,
+			LeaseName:      "test-controller",
+			LeaseNamespace: "kube-system",
+			Identity:       identity,
+			LeaseDuration:  time.Second,
+			RenewDeadline:  500 * time.Millisecond,
+			RetryPeriod:    50 * time.Millisecond,
+		},
+	})
+}
+
+func waitUntil(t *testing.T, ctx context.Context, what string, cond func() bool) {
+	t.Helper()
+	for !cond() {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %s", what)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestLeaderElection(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	store := newAPIServer()
+
+	var reconciles [2]atomic.Int32
+	ctrls := make([]*Controller[*corev1.ConfigMap], 2)
+	cancels := make([]context.CancelFunc, 2)
+	dones := make([]chan error, 2)
+	for i, id := range []string{"a", "b"} {
+		ctrls[i] = newElectedController(store, id, func(context.Context) error {
+			reconciles[i].Add(1)
+			return nil
+		})
+		var runCtx context.Context
+		runCtx, cancels[i] = context.WithCancel(ctx)
+		dones[i] = make(chan error, 1)
+		go func() { dones[i] <- ctrls[i].Run(runCtx) }()
+	}
+
+	waitUntil(t, ctx, "a leader to reconcile", func() bool { return reconciles[0].Load()+reconciles[1].Load() > 0 })
+	leader := 0
+	if leaseHolder(store) == "b" {
+		leader = 1
+	}
+	follower := 1 - leader
+	if !ctrls[leader].IsLeader() || ctrls[follower].IsLeader() {
+		t.Fatalf("expected only %d to lead, got IsLeader %t and %t", leader, ctrls[0].IsLeader(), ctrls[1].IsLeader())
+	}
+	waitUntil(t, ctx, "the follower to sync", ctrls[follower].HasSynced)
+	time.Sleep(200 * time.Millisecond)
+	if n := reconciles[follower].Load(); n != 0 {
+		t.Errorf("follower reconciled %d times", n)
+	}
+
+	// Stopping the leader releases the lease, and the follower takes over.
+	cancels[leader]()
+	if err := <-dones[leader]; err != nil {
+		t.Errorf("leader's Run returned %v", err)
+	}
+	waitUntil(t, ctx, "the follower to reconcile", func() bool { return reconciles[follower].Load() > 0 })
+	if !ctrls[follower].IsLeader() {
+		t.Error("expected the follower to lead")
+	}
+	cancels[follower]()
+	if err := <-dones[follower]; err != nil {
+		t.Errorf("follower's Run returned %v", err)
+	}
+}
+
+func TestLeadershipLost(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	store := newAPIServer()
+
+	started := make(chan struct{})
+	canceled := make(chan error, 1)
+	ctrl := newElectedController(store, "a", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return ctx.Err()
+	})
+	var stopped atomic.Bool
+	ctrl.leaderElection.OnStoppedLeading = func() { stopped.Store(true) }
+
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(ctx) }()
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for reconcile")
+	}
+
+	// The lease can no longer be renewed.
+	store.failWrites.Store(true)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrLeadershipLost) {
+			t.Errorf("Run returned %v, want ErrLeadershipLost", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for Run to return")
+	}
+	select {
+	case err := <-canceled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("in-flight reconcile's context error = %v, want Canceled", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("in-flight reconcile was not canceled")
+	}
+	if !stopped.Load() {
+		t.Error("OnStoppedLeading was not called")
+	}
+	if ctrl.IsLeader() {
+		t.Error("expected IsLeader to be false after losing the lease")
+	}
+}
+
+func TestLeaderElectionDrainsBeforeRelease(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	store := newAPIServer()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	ctrl := newElectedController(store, "a", func(context.Context) error {
+		once.Do(func() { close(started) })
+		<-release
+		return nil
+	})
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(runCtx) }()
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for reconcile")
+	}
+
+	stop()
+	time.Sleep(200 * time.Millisecond)
+	if got := leaseHolder(store); got != "a" {
+		t.Errorf("lease holder = %q while a reconcile is in flight, want a", got)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+	if got := leaseHolder(store); got != "" {
+		t.Errorf("lease holder = %q after Run returned, want it released", got)
+	}
+}
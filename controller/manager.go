@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/imjasonh/client-go2/generic"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// leasesGVR is the resource of coordination.k8s.io/v1 Leases.
+var leasesGVR = schema.GroupVersionResource{Group: "coordination.k8s.io", Version: "v1", Resource: "leases"}
+
+// ManagerOptions configures a Manager.
+type ManagerOptions struct {
+	// CacheSyncTimeout bounds how long Run waits for the caches of every
+	// registered controller to sync. Defaults to 2 minutes.
+	CacheSyncTimeout time.Duration
+
+	// LeaderElection, if set, makes replicas of the manager elect a leader,
+	// and only the leader runs the workers of its controllers. If its Client
+	// is not set, one is created from the manager's ClientFactory.
+	LeaderElection *LeaderElection
+}
+
+// Manager runs many controllers together, sharing one InformerFactory and
+// one ClientFactory among them.
+//
+// Controllers are added with Register. When the manager runs, it starts the
+// informers of every controller, waits once for all of them to sync, and
+// then starts every controller's workers, under a single leader election if
+// configured. When its context is canceled, every controller is shut down.
+//
+//	mgr, err := controller.NewManager(config, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	pods, err := generic.ClientFor[*corev1.Pod](mgr.ClientFactory())
+//	if _, err := controller.Register(mgr, pods, podReconciler, nil); err != nil {
+//	    return err
+//	}
+//	return mgr.Run(ctx)
+type Manager struct {
+	clients          *generic.ClientFactory
+	informers        *generic.InformerFactory
+	cacheSyncTimeout time.Duration
+	leaderElection   *LeaderElection
+
+	mu          sync.Mutex
+	controllers []managed
+	running     bool
+
+	synced  atomic.Bool
+	leading atomic.Bool
+}
+
+// managed is a controller run by a Manager. It is implemented by
+// *Controller[T] for every T.
+type managed interface {
+	inspectable
+	Name() string
+	startInformers(ctx context.Context) ([]cache.InformerSynced, error)
+	startWorkers(ctx context.Context)
+	stopWorkers(ctx context.Context)
+	setSynced(bool)
+	setLeading(bool)
+	shutdown()
+}
+
+// NewManager returns a Manager whose clients and informers use config.
+func NewManager(config *rest.Config, opts *ManagerOptions) (*Manager, error) {
+	if opts == nil {
+		opts = &ManagerOptions{}
+	}
+	clients, err := generic.NewClientFactory(config)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manager{
+		clients:          clients,
+		informers:        generic.NewInformerFactory(),
+		cacheSyncTimeout: opts.CacheSyncTimeout,
+	}
+	if m.cacheSyncTimeout <= 0 {
+		m.cacheSyncTimeout = defaultCacheSyncTimeout
+	}
+	if opts.LeaderElection != nil {
+		le := *opts.LeaderElection
+		if le.Client.RESTClient() == nil {
+			le.Client = generic.ClientForGVR[*coordinationv1.Lease](clients, leasesGVR)
+		}
+		m.leaderElection = &le
+	}
+	return m, nil
+}
+
+// ClientFactory returns the manager's ClientFactory, for creating the
+// clients of the controllers it runs.
+func (m *Manager) ClientFactory() *generic.ClientFactory {
+	return m.clients
+}
+
+// InformerFactory returns the informer factory shared by the manager's
+// controllers. Informers requested from it before Run are started with them.
+func (m *Manager) InformerFactory() *generic.InformerFactory {
+	return m.informers
+}
+
+// Register creates a controller like New and adds it to m.
+//
+// The controller uses m's InformerFactory, and m's cache sync timeout and
+// leader election rather than its own, so opts must not set a different
+// InformerFactory or LeaderElection. Controller names must be unique within
+// a manager. Controllers must be registered before m runs.
+func Register[T runtime.Object](m *Manager, client generic.Client[T], reconciler Reconciler[T], opts *Options[T]) (*Controller[T], error) {
+	if opts == nil {
+		opts = &Options[T]{}
+	}
+	switch {
+	case opts.InformerFactory != nil && opts.InformerFactory != m.informers:
+		return nil, errors.New("controllers run by a manager must use its InformerFactory")
+	case opts.LeaderElection != nil:
+		return nil, errors.New("controllers run by a manager use its leader election; set ManagerOptions.LeaderElection instead")
+	}
+	opts.InformerFactory = m.informers
+	c := New(client, reconciler, opts)
+	c.elected = m.leaderElection != nil
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.running {
+		c.shutdown()
+		return nil, errors.New("cannot register a controller with a running manager")
+	}
+	for _, existing := range m.controllers {
+		if existing.Name() == c.Name() {
+			c.shutdown()
+			return nil, fmt.Errorf("a controller named %q is already registered", c.Name())
+		}
+	}
+	m.controllers = append(m.controllers, c)
+	return c, nil
+}
+
+// Run runs every registered controller until ctx is canceled, or until
+// leadership is lost, in which case it returns ErrLeadershipLost. Like
+// Controller.Run, it stops each controller gracefully before returning.
+// A Manager can only be run once.
+func (m *Manager) Run(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return errors.New("manager is already running")
+	}
+	m.running = true
+	controllers := slices.Clone(m.controllers)
+	m.mu.Unlock()
+
+	defer func() {
+		for _, c := range controllers {
+			c.shutdown()
+		}
+	}()
+
+	clog.InfoContext(ctx, "starting manager", "controllers", len(controllers))
+
+	var synced []cache.InformerSynced
+	for _, c := range controllers {
+		s, err := c.startInformers(ctx)
+		if err != nil {
+			return fmt.Errorf("controller %s: %w", c.Name(), err)
+		}
+		synced = append(synced, s...)
+	}
+	m.informers.Start(ctx)
+	if err := waitForCacheSync(ctx, "manager", m.cacheSyncTimeout, synced, m.informers); err != nil {
+		return err
+	}
+
+	m.setSynced(controllers, true)
+	defer m.setSynced(controllers, false)
+
+	return runWorkers(ctx, "manager", m.leaderElection, func(leading bool) {
+		m.leading.Store(leading)
+		for _, c := range controllers {
+			c.setLeading(leading)
+		}
+	}, controllers)
+}
+
+// setSynced records whether the caches of m and controllers have synced.
+func (m *Manager) setSynced(controllers []managed, synced bool) {
+	m.synced.Store(synced)
+	for _, c := range controllers {
+		c.setSynced(synced)
+	}
+}
+
+// HasSynced returns true once Run has synced the caches of every
+// controller. It can be used as a readiness check.
+func (m *Manager) HasSynced() bool {
+	return m.synced.Load()
+}
+
+// IsLeader returns true if the manager is the elected leader. It is always
+// false without leader election.
+func (m *Manager) IsLeader() bool {
+	return m.leading.Load()
+}
+
+// Status returns the state of each registered controller, in the order
+// they were registered.
+func (m *Manager) Status() []ControllerStatus {
+	controllers := m.registered()
+	statuses := make([]ControllerStatus, 0, len(controllers))
+	for _, c := range controllers {
+		statuses = append(statuses, c.Status())
+	}
+	return statuses
+}
+
+// Handler returns an http.Handler serving the health of every registered
+// controller, like Controller.Handler: /healthz and /readyz fail if any
+// controller fails them, and /debug/queue lists every controller's queue.
+func (m *Manager) Handler() http.Handler {
+	return healthHandler(func() []inspectable {
+		var controllers []inspectable
+		for _, c := range m.registered() {
+			controllers = append(controllers, c)
+		}
+		return controllers
+	})
+}
+
+// registered returns the registered controllers.
+func (m *Manager) registered() []managed {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.controllers)
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imjasonh/client-go2/generic"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestManager(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	mgr, err := NewManager(server.config(), nil)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	client := generic.ClientForGVR[*corev1.ConfigMap](mgr.ClientFactory(), schema.GroupVersionResource{Version: "v1", Resource: "configmaps"})
+
+	var reconciles [2]atomic.Int32
+	for i, name := range []string{"a", "b"} {
+		if _, err := Register(mgr, client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+			reconciles[i].Add(1)
+			return nil
+		}), &Options[*corev1.ConfigMap]{Name: name}); err != nil {
+			t.Fatalf("Register(%s) failed: %v", name, err)
+		}
+	}
+	if mgr.HasSynced() {
+		t.Error("expected HasSynced to be false before Run")
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- mgr.Run(ctx) }()
+
+	waitUntil(t, ctx, "both controllers to reconcile", func() bool {
+		return reconciles[0].Load() > 0 && reconciles[1].Load() > 0
+	})
+	if !mgr.HasSynced() {
+		t.Error("expected HasSynced to be true")
+	}
+	if mgr.IsLeader() {
+		t.Error("expected IsLeader to be false without leader election")
+	}
+	want := []ControllerStatus{{Name: "a", Synced: true, Workers: 1}, {Name: "b", Synced: true, Workers: 1}}
+	if got := mgr.Status(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Status() = %+v, want %+v", got, want)
+	}
+
+	rec := httptest.NewRecorder()
+	mgr.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, "[+]a ok") || !strings.Contains(body, "[+]b ok") {
+		t.Errorf("/readyz = %d %q", rec.Code, body)
+	}
+
+	if got, want := server.requestLog(), []string{"GET /api/v1/configmaps"}; !slices.Equal(got, want) {
+		t.Errorf("expected the controllers to share one informer, got requests %v", got)
+	}
+
+	if _, err := Register(mgr, client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+		return nil
+	}), &Options[*corev1.ConfigMap]{Name: "c"}); err == nil {
+		t.Error("expected registering with a running manager to fail")
+	}
+	if err := mgr.Run(ctx); err == nil {
+		t.Error("expected running a running manager to fail")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+	if mgr.HasSynced() {
+		t.Error("expected HasSynced to be false after Run returns")
+	}
+}
+
+func TestManagerWaitsForOwnedCacheSync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	release := server.holdLists("/api/v1/secrets")
+	mgr, err := NewManager(server.config(), nil)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	var reconciles atomic.Int32
+	ctrl, err := Register(mgr, generic.ClientForGVR[*corev1.ConfigMap](mgr.ClientFactory(), schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}),
+		ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+			reconciles.Add(1)
+			return nil
+		}), &Options[*corev1.ConfigMap]{Name: "a"})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	secrets := generic.ClientForGVR[*corev1.Secret](mgr.ClientFactory(), schema.GroupVersionResource{Version: "v1", Resource: "secrets"})
+	if _, err := WatchOwned(ctx, ctrl, secrets, false); err != nil {
+		t.Fatalf("WatchOwned failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- mgr.Run(ctx) }()
+
+	time.Sleep(200 * time.Millisecond)
+	if n := reconciles.Load(); n != 0 || mgr.HasSynced() {
+		t.Fatalf("expected no reconciles and not synced before the owned cache synced, got %d reconciles, synced=%t", n, mgr.HasSynced())
+	}
+	release()
+	waitUntil(t, ctx, "the controller to reconcile", func() bool { return reconciles.Load() > 0 && mgr.HasSynced() })
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+}
+
+func TestManagerLeaderElection(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	mgr, err := NewManager(server.config(), &ManagerOptions{
+		LeaderElection: &LeaderElection{
+			Client: generic.NewClientGVR[*coordinationv1.Lease](leasesGVR,
+				newAPIServer().config()),
+			LeaseName:      "test-manager",
+			LeaseNamespace: "kube-system",
+			Identity:       "a",
+			LeaseDuration:  time.Second,
+			RenewDeadline:  500 * time.Millisecond,
+			RetryPeriod:    50 * time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	client := generic.ClientForGVR[*corev1.ConfigMap](mgr.ClientFactory(), schema.GroupVersionResource{Version: "v1", Resource: "configmaps"})
+	var reconciles atomic.Int32
+	ctrl, err := Register(mgr, client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+		reconciles.Add(1)
+		return nil
+	}), &Options[*corev1.ConfigMap]{Name: "test"})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- mgr.Run(ctx) }()
+
+	waitUntil(t, ctx, "the leader to reconcile", func() bool { return reconciles.Load() > 0 })
+	if !mgr.IsLeader() || !ctrl.IsLeader() {
+		t.Errorf("expected the manager and its controller to lead, got %t and %t", mgr.IsLeader(), ctrl.IsLeader())
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+	if mgr.IsLeader() || ctrl.IsLeader() {
+		t.Error("expected leadership to end when Run returns")
+	}
+}
+
+func TestManagerRegisterErrors(t *testing.T) {
+	mgr, err := NewManager(&rest.Config{Host: "http://localhost"}, nil)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	client := generic.ClientForGVR[*corev1.ConfigMap](mgr.ClientFactory(), schema.GroupVersionResource{Version: "v1", Resource: "configmaps"})
+	reconciler := ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error { return nil })
+
+	if _, err := Register(mgr, client, reconciler, &Options[*corev1.ConfigMap]{Name: "a"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	for _, tt := range []struct {
+		name string
+		opts *Options[*corev1.ConfigMap]
+	}{
+		{"duplicate name", &Options[*corev1.ConfigMap]{Name: "a"}},
+		{"other informer factory", &Options[*corev1.ConfigMap]{Name: "b", InformerFactory: generic.NewInformerFactory()}},
+		{"own leader election", &Options[*corev1.ConfigMap]{Name: "c", LeaderElection: &LeaderElection{}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Register(mgr, client, reconciler, tt.opts); err == nil {
+				t.Error("expected Register to fail")
+			}
+		})
+	}
+	if got := len(mgr.Status()); got != 1 {
+		t.Errorf("expected 1 registered controller, got %d", got)
+	}
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Outcome is the result of a single reconcile, as reported to Metrics.
+type Outcome string
+
+const (
+	// OutcomeSuccess means the reconcile succeeded.
+	OutcomeSuccess Outcome = "success"
+	// OutcomeError means the reconcile failed and will be retried with backoff.
+	OutcomeError Outcome = "error"
+	// OutcomeRequeue means the reconciler asked to be requeued.
+	OutcomeRequeue Outcome = "requeue"
+	// OutcomePermanentError means the reconcile failed and will not be retried.
+	OutcomePermanentError Outcome = "permanent_error"
+	// OutcomePanic means the reconciler panicked.
+	OutcomePanic Outcome = "panic"
+	// OutcomeTimeout means the reconcile did not finish within the
+	// controller's ReconcileTimeout.
+	OutcomeTimeout Outcome = "timeout"
+)
+
+// Metrics receives measurements from a Controller.
+//
+// The embedded workqueue.MetricsProvider is used to instrument the
+// controller's default queue (depth, adds, queue latency, work duration and
+// retries), with the controller's name as the queue name.
+//
+// Implementations must be safe for concurrent use. See the metrics package
+// for a Prometheus implementation.
+type Metrics interface {
+	workqueue.MetricsProvider
+
+	// ObserveReconcile is called after each reconcile with its outcome and
+	// how long it took.
+	ObserveReconcile(controller string, outcome Outcome, duration time.Duration)
+}
+
+// outcomeFor classifies the error returned from processing an item.
+func outcomeFor(err error) Outcome {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case IsPanicError(err):
+		return OutcomePanic
+	case IsTimeoutError(err):
+		return OutcomeTimeout
+	case IsPermanentError(err):
+		return OutcomePermanentError
+	case IsRequeueError(err):
+		return OutcomeRequeue
+	default:
+		return OutcomeError
+	}
+}
+
+// observeReconcile reports a reconcile to the controller's metrics, if any.
+func (c *Controller[T]) observeReconcile(err error, duration time.Duration) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.ObserveReconcile(c.name, outcomeFor(err), duration)
+}
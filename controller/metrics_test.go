@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestOutcomeFor(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want Outcome
+	}{
+		{nil, OutcomeSuccess},
+		{errors.New("boom"), OutcomeError},
+		{PermanentError(errors.New("bad")), OutcomePermanentError},
+		{fmt.Errorf("wrapped: %w", PermanentError(errors.New("bad"))), OutcomePermanentError},
+		{RequeueAfter(time.Second), OutcomeRequeue},
+		{RequeueImmediately(), OutcomeRequeue},
+		{&panicError{value: "boom"}, OutcomePanic},
+		{PermanentError(&panicError{value: "boom"}), OutcomePanic},
+		{fmt.Errorf("%w: %w", ErrReconcileTimeout, context.DeadlineExceeded), OutcomeTimeout},
+	} {
+		if got := outcomeFor(tt.err); got != tt.want {
+			t.Errorf("outcomeFor(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Middleware wraps a Reconciler with behavior that runs around each of its
+// Reconcile calls, such as logging, instrumentation or filtering. A
+// Middleware is typically written with ReconcilerFunc:
+//
+//	func requireAnnotation[T runtime.Object](name string) controller.Middleware[T] {
+//	    return func(next controller.Reconciler[T]) controller.Reconciler[T] {
+//	        return controller.ReconcilerFunc[T](func(ctx context.Context, obj T) error {
+//	            if m, err := meta.Accessor(obj); err == nil && m.GetAnnotations()[name] == "" {
+//	                return nil
+//	            }
+//	            return next.Reconcile(ctx, obj)
+//	        })
+//	    }
+//	}
+type Middleware[T runtime.Object] func(next Reconciler[T]) Reconciler[T]
+
+// Chain returns r wrapped with mws. The first middleware is the outermost:
+// it is called first, and sees the result of all the others.
+//
+//	reconciler := controller.Chain[*corev1.Pod](podReconciler,
+//	    controller.Recover[*corev1.Pod](),
+//	    controller.LogFields[*corev1.Pod](nil),
+//	    controller.SkipByLabel[*corev1.Pod](labels.SelectorFromSet(labels.Set{"paused": "true"})),
+//	)
+//
+// Only Reconcile is wrapped. If r implements DeletionReconciler or
+// Finalizer, so does the returned Reconciler, calling r's methods directly.
+func Chain[T runtime.Object](r Reconciler[T], mws ...Middleware[T]) Reconciler[T] {
+	wrapped := r
+	for i := len(mws) - 1; i >= 0; i-- {
+		wrapped = mws[i](wrapped)
+	}
+	d, isDeletion := r.(DeletionReconciler[T])
+	f, isFinalizer := r.(Finalizer[T])
+	switch {
+	case isDeletion && isFinalizer:
+		return struct {
+			Reconciler[T]
+			DeletionReconciler[T]
+			Finalizer[T]
+		}{wrapped, d, f}
+	case isDeletion:
+		return struct {
+			Reconciler[T]
+			DeletionReconciler[T]
+		}{wrapped, d}
+	case isFinalizer:
+		return struct {
+			Reconciler[T]
+			Finalizer[T]
+		}{wrapped, f}
+	}
+	return wrapped
+}
+
+// LogFields is a Middleware that adds the object's namespace and name, and
+// any fields returned by extra, to the context's clog values, so that every
+// message logged while reconciling it includes them. extra may be nil.
+func LogFields[T runtime.Object](extra func(obj T) []any) Middleware[T] {
+	return func(next Reconciler[T]) Reconciler[T] {
+		return ReconcilerFunc[T](func(ctx context.Context, obj T) error {
+			if m, err := meta.Accessor(obj); err == nil {
+				ctx = clog.WithValues(ctx, "namespace", m.GetNamespace(), "name", m.GetName())
+			}
+			if extra != nil {
+				ctx = clog.WithValues(ctx, extra(obj)...)
+			}
+			return next.Reconcile(ctx, obj)
+		})
+	}
+}
+
+// Timing is a Middleware that calls observe with how long each reconcile
+// took and the error it returned. If observe is nil, each reconcile is
+// logged with its duration instead.
+func Timing[T runtime.Object](observe func(ctx context.Context, obj T, duration time.Duration, err error)) Middleware[T] {
+	if observe == nil {
+		observe = func(ctx context.Context, obj T, duration time.Duration, err error) {
+			clog.InfoContext(ctx, "reconciled", "key", objKey(obj), "duration", duration, "error", err)
+		}
+	}
+	return func(next Reconciler[T]) Reconciler[T] {
+		return ReconcilerFunc[T](func(ctx context.Context, obj T) error {
+			start := time.Now()
+			err := next.Reconcile(ctx, obj)
+			observe(ctx, obj, time.Since(start), err)
+			return err
+		})
+	}
+}
+
+// Recover is a Middleware that recovers from panics in the middlewares and
+// Reconciler it wraps, logging them with their stack and returning them as
+// errors for which IsPanicError is true. Controllers recover from panics
+// themselves; Recover lets middlewares outside it observe them as errors.
+func Recover[T runtime.Object]() Middleware[T] {
+	return func(next Reconciler[T]) Reconciler[T] {
+		return ReconcilerFunc[T](func(ctx context.Context, obj T) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = panicked(ctx, objKey(obj), r)
+				}
+			}()
+			return next.Reconcile(ctx, obj)
+		})
+	}
+}
+
+// SkipByLabel is a Middleware that skips reconciling objects whose labels
+// match selector, returning nil without calling the Reconciler.
+func SkipByLabel[T runtime.Object](selector labels.Selector) Middleware[T] {
+	return func(next Reconciler[T]) Reconciler[T] {
+		return ReconcilerFunc[T](func(ctx context.Context, obj T) error {
+			if m, err := meta.Accessor(obj); err == nil && selector.Matches(labels.Set(m.GetLabels())) {
+				clog.DebugContext(ctx, "skipping reconcile of object matching label selector", "key", objKey(obj), "selector", selector.String())
+				return nil
+			}
+			return next.Reconcile(ctx, obj)
+		})
+	}
+}
+
+// objKey returns the namespace/name key of obj, for logging.
+func objKey(obj runtime.Object) string {
+	key, _ := cache.MetaNamespaceKeyFunc(obj)
+	return key
+}
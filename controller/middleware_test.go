@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware[*corev1.Pod] {
+		return func(next Reconciler[*corev1.Pod]) Reconciler[*corev1.Pod] {
+			return ReconcilerFunc[*corev1.Pod](func(ctx context.Context, pod *corev1.Pod) error {
+				calls = append(calls, name+" before")
+				err := next.Reconcile(ctx, pod)
+				calls = append(calls, name+" after")
+				return err
+			})
+		}
+	}
+	wantErr := errors.New("boom")
+	r := Chain[*corev1.Pod](ReconcilerFunc[*corev1.Pod](func(context.Context, *corev1.Pod) error {
+		calls = append(calls, "reconcile")
+		return wantErr
+	}), record("outer"), record("inner"))
+
+	if err := r.Reconcile(context.Background(), &corev1.Pod{}); err != wantErr {
+		t.Errorf("Reconcile returned %v, want %v", err, wantErr)
+	}
+	want := "outer before,inner before,reconcile,inner after,outer after"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+}
+
+func TestChainOptionalInterfaces(t *testing.T) {
+	if _, ok := Chain[*corev1.ConfigMap](ReconcilerFunc[*corev1.ConfigMap](nil)).(DeletionReconciler[*corev1.ConfigMap]); ok {
+		t.Error("expected a chained ReconcilerFunc not to be a DeletionReconciler")
+	}
+
+	d := &deletionReconciler{}
+	chained := Chain[*corev1.ConfigMap](d, SkipByLabel[*corev1.ConfigMap](labels.Everything()))
+	dr, ok := chained.(DeletionReconciler[*corev1.ConfigMap])
+	if !ok {
+		t.Fatal("expected a chained DeletionReconciler to be a DeletionReconciler")
+	}
+	if _, ok := chained.(Finalizer[*corev1.ConfigMap]); ok {
+		t.Error("expected a chained DeletionReconciler not to be a Finalizer")
+	}
+	if err := chained.Reconcile(context.Background(), &corev1.ConfigMap{}); err != nil {
+		t.Errorf("Reconcile failed: %v", err)
+	}
+	if err := dr.ReconcileDeleted(context.Background(), "default/cm"); err != nil {
+		t.Errorf("ReconcileDeleted failed: %v", err)
+	}
+	if reconciled, deleted := d.counts(); reconciled != 0 || deleted != 1 {
+		t.Errorf("expected only ReconcileDeleted to reach the reconciler, got %d reconciles and %d deletions", reconciled, deleted)
+	}
+
+	if _, ok := Chain[*corev1.Pod](&finalizingReconciler{}).(Finalizer[*corev1.Pod]); !ok {
+		t.Error("expected a chained Finalizer to be a Finalizer")
+	}
+}
+
+func TestLogFields(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := clog.WithLogger(context.Background(), clog.New(slog.NewTextHandler(&buf, nil)))
+	r := Chain[*corev1.Pod](ReconcilerFunc[*corev1.Pod](func(ctx context.Context, _ *corev1.Pod) error {
+		clog.InfoContext(ctx, "reconciling")
+		return nil
+	}), LogFields(func(pod *corev1.Pod) []any { return []any{"node", pod.Spec.NodeName} }))
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "p"}, Spec: corev1.PodSpec{NodeName: "n1"}}
+	if err := r.Reconcile(ctx, pod); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	for _, want := range []string{"namespace=default", "name=p", "node=n1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected log to contain %q, got %q", want, buf.String())
+		}
+	}
+}
+
+func TestTiming(t *testing.T) {
+	wantErr := errors.New("boom")
+	var observed time.Duration
+	var observedErr error
+	r := Chain[*corev1.Pod](ReconcilerFunc[*corev1.Pod](func(context.Context, *corev1.Pod) error {
+		time.Sleep(10 * time.Millisecond)
+		return wantErr
+	}), Timing(func(_ context.Context, _ *corev1.Pod, d time.Duration, err error) {
+		observed, observedErr = d, err
+	}))
+
+	if err := r.Reconcile(context.Background(), &corev1.Pod{}); err != wantErr {
+		t.Errorf("Reconcile returned %v, want %v", err, wantErr)
+	}
+	if observed < 10*time.Millisecond || observedErr != wantErr {
+		t.Errorf("observed %v and %v, want at least 10ms and %v", observed, observedErr, wantErr)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var observedErr error
+	r := Chain[*corev1.Pod](ReconcilerFunc[*corev1.Pod](func(context.Context, *corev1.Pod) error {
+		panic("boom")
+	}), Timing(func(_ context.Context, _ *corev1.Pod, _ time.Duration, err error) {
+		observedErr = err
+	}), Recover[*corev1.Pod]())
+
+	err := r.Reconcile(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "p"}})
+	if !IsPanicError(err) || !IsPanicError(observedErr) {
+		t.Errorf("expected panic errors, got %v and observed %v", err, observedErr)
+	}
+}
+
+func TestSkipByLabel(t *testing.T) {
+	var reconciled []string
+	r := Chain[*corev1.Pod](ReconcilerFunc[*corev1.Pod](func(_ context.Context, pod *corev1.Pod) error {
+		reconciled = append(reconciled, pod.Name)
+		return nil
+	}), SkipByLabel[*corev1.Pod](labels.SelectorFromSet(labels.Set{"paused": "true"})))
+
+	for _, pod := range []*corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "paused", Labels: map[string]string{"paused": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "running", Labels: map[string]string{"paused": "false"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unlabelled"}},
+	} {
+		if err := r.Reconcile(context.Background(), pod); err != nil {
+			t.Errorf("Reconcile(%s) failed: %v", pod.Name, err)
+		}
+	}
+	if got := strings.Join(reconciled, ","); got != "running,unlabelled" {
+		t.Errorf("reconciled %s, want running,unlabelled", got)
+	}
+}
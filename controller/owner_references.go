@@ -72,7 +72,31 @@ func getObjectMetaFromObject(obj runtime.Object) (metav1.Object, error) {
 // WatchOwned configures the controller to watch resources of type O and enqueue
 // their owners of type T when they change.
 // It returns a Lister for the owned resources.
-func WatchOwned[T, O runtime.Object](ctx context.Context, c *Controller[T], ownedClient generic.Client[O], isController bool) (*generic.Lister[O], error) {
+//
+// Updates of owned resources only enqueue their owners if every one of
+// predicates allows them.
+//
+// If the controller has an InformerFactory, the owned resources are watched
+// with a shared informer from it, and the returned Lister is populated once
+// the controller starts the factory. Otherwise WatchOwned blocks until the
+// owned resources have synced.
+func WatchOwned[T, O runtime.Object](ctx context.Context, c *Controller[T], ownedClient generic.Client[O], isController bool, predicates ...Predicate[O]) (*generic.Lister[O], error) {
+	informer, err := watchOwned(ctx, c, ownedClient, isController, predicates)
+	if err != nil {
+		return nil, err
+	}
+	if c.factory == nil {
+		if err := informer.WaitForSync(ctx); err != nil {
+			informer.Stop()
+			return nil, fmt.Errorf("failed to start informer for owned resources: %w", err)
+		}
+	}
+	return informer.Lister(), nil
+}
+
+// watchOwned starts watching resources of type O for WatchOwned, without
+// waiting for them to sync.
+func watchOwned[T, O runtime.Object](ctx context.Context, c *Controller[T], ownedClient generic.Client[O], isController bool, predicates []Predicate[O]) (*generic.Informer[O], error) {
 	ownerGVK := c.client.GVK()
 
 	// Create handler for owned resources
@@ -81,6 +105,10 @@ func WatchOwned[T, O runtime.Object](ctx context.Context, c *Controller[T], owne
 			c.enqueueOwners(ctx, obj, ownerGVK, isController)
 		},
 		OnUpdate: func(key string, oldObj, newObj O) {
+			if !allowed(predicates, oldObj, newObj) {
+				clog.DebugContext(ctx, "owned resource update filtered by predicates", "key", key)
+				return
+			}
 			// Enqueue owners from both old and new objects
 			// This handles cases where ownership changes
 			c.enqueueOwners(ctx, oldObj, ownerGVK, isController)
@@ -94,18 +122,23 @@ func WatchOwned[T, O runtime.Object](ctx context.Context, c *Controller[T], owne
 		},
 	}
 
-	opts := &generic.InformOptions{}
-	if c.namespace != "" {
-		opts.ListOptions.FieldSelector = fmt.Sprintf("metadata.namespace=%s", c.namespace)
+	opts := &generic.InformOptions[O]{Namespace: c.namespace, NoWaitForSync: true}
+
+	// Share the controller's informers if it has a factory.
+	if c.factory != nil {
+		informer := ownedClient.SharedInformer(c.factory, opts)
+		if _, err := informer.AddHandler(handler); err != nil {
+			return nil, fmt.Errorf("failed to add event handler for owned resources: %w", err)
+		}
+		return informer, nil
 	}
 
 	// Start watching the owned resources
-	lister, err := ownedClient.Inform(ctx, handler, opts)
+	informer, err := ownedClient.Inform(ctx, handler, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start informer for owned resources: %w", err)
 	}
-
-	return lister, nil
+	return informer, nil
 }
 
 // enqueueOwners finds owners of the given object and enqueues them
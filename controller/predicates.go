@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Predicate decides whether an update of an object from old to new should
+// enqueue work, returning false to ignore it.
+//
+// Predicates only filter updates: adds and deletes are always enqueued, so
+// that every object is reconciled at least once and deletions are observed.
+// Any func(old, new T) bool can be used as a Predicate, as can the generic
+// functions in this package, such as GenerationChanged[*corev1.Pod].
+type Predicate[T runtime.Object] func(old, new T) bool
+
+// GenerationChanged is a Predicate that allows updates that change the
+// object's metadata.generation, which the API server increments when its
+// spec changes but not its status or metadata. It ignores the status updates
+// a controller makes itself.
+func GenerationChanged[T runtime.Object](old, new T) bool {
+	o, n, ok := accessors(old, new)
+	return !ok || o.GetGeneration() != n.GetGeneration()
+}
+
+// LabelsChanged is a Predicate that allows updates that change the object's
+// labels.
+func LabelsChanged[T runtime.Object](old, new T) bool {
+	o, n, ok := accessors(old, new)
+	return !ok || !reflect.DeepEqual(o.GetLabels(), n.GetLabels())
+}
+
+// AnnotationsChanged is a Predicate that allows updates that change the
+// object's annotations.
+func AnnotationsChanged[T runtime.Object](old, new T) bool {
+	o, n, ok := accessors(old, new)
+	return !ok || !reflect.DeepEqual(o.GetAnnotations(), n.GetAnnotations())
+}
+
+// ResourceVersionChanged is a Predicate that allows updates that change the
+// object's resourceVersion, ignoring the periodic resyncs of unchanged
+// objects.
+func ResourceVersionChanged[T runtime.Object](old, new T) bool {
+	o, n, ok := accessors(old, new)
+	return !ok || o.GetResourceVersion() != n.GetResourceVersion()
+}
+
+// Or returns a Predicate that allows updates allowed by any of predicates.
+//
+//	Predicates: []controller.Predicate[*appsv1.Deployment]{
+//	    controller.Or(controller.GenerationChanged[*appsv1.Deployment], controller.LabelsChanged[*appsv1.Deployment]),
+//	},
+func Or[T runtime.Object](predicates ...Predicate[T]) Predicate[T] {
+	return func(old, new T) bool {
+		for _, p := range predicates {
+			if p(old, new) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// allowed returns true if every predicate allows the update from old to new.
+func allowed[T runtime.Object](predicates []Predicate[T], old, new T) bool {
+	for _, p := range predicates {
+		if !p(old, new) {
+			return false
+		}
+	}
+	return true
+}
+
+// accessors returns the metadata of old and new, and false if either has
+// none, in which case predicates allow the update.
+func accessors(old, new runtime.Object) (metav1.Object, metav1.Object, bool) {
+	o, err := meta.Accessor(old)
+	if err != nil {
+		return nil, nil, false
+	}
+	n, err := meta.Accessor(new)
+	if err != nil {
+		return nil, nil, false
+	}
+	return o, n, true
+}
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imjasonh/client-go2/generic"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestPredicates(t *testing.T) {
+	base := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:            "cm",
+		Generation:      1,
+		ResourceVersion: "1",
+		Labels:          map[string]string{"app": "a"},
+		Annotations:     map[string]string{"note": "a"},
+	}}
+	changed := func(f func(*corev1.ConfigMap)) *corev1.ConfigMap {
+		cm := base.DeepCopy()
+		f(cm)
+		return cm
+	}
+	generation := changed(func(cm *corev1.ConfigMap) { cm.Generation = 2 })
+	labels := changed(func(cm *corev1.ConfigMap) { cm.Labels["app"] = "b" })
+	annotations := changed(func(cm *corev1.ConfigMap) { cm.Annotations = nil })
+	resourceVersion := changed(func(cm *corev1.ConfigMap) { cm.ResourceVersion = "2" })
+
+	for _, tt := range []struct {
+		name      string
+		predicate Predicate[*corev1.ConfigMap]
+		allowed   []*corev1.ConfigMap
+	}{
+		{"GenerationChanged", GenerationChanged[*corev1.ConfigMap], []*corev1.ConfigMap{generation}},
+		{"LabelsChanged", LabelsChanged[*corev1.ConfigMap], []*corev1.ConfigMap{labels}},
+		{"AnnotationsChanged", AnnotationsChanged[*corev1.ConfigMap], []*corev1.ConfigMap{annotations}},
+		{"ResourceVersionChanged", ResourceVersionChanged[*corev1.ConfigMap], []*corev1.ConfigMap{resourceVersion}},
+		{"Or", Or(GenerationChanged[*corev1.ConfigMap], LabelsChanged[*corev1.ConfigMap]), []*corev1.ConfigMap{generation, labels}},
+		{"func", func(_, new *corev1.ConfigMap) bool { return new.Annotations == nil }, []*corev1.ConfigMap{annotations}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, new := range []*corev1.ConfigMap{base, generation, labels, annotations, resourceVersion} {
+				want := false
+				for _, a := range tt.allowed {
+					want = want || a == new
+				}
+				if got := tt.predicate(base, new); got != want {
+					t.Errorf("update to %+v: got %t, want %t", new.ObjectMeta, got, want)
+				}
+			}
+		})
+	}
+
+	if !allowed[*corev1.ConfigMap](nil, base, base) {
+		t.Error("expected no predicates to allow every update")
+	}
+	if allowed([]Predicate[*corev1.ConfigMap]{GenerationChanged[*corev1.ConfigMap], LabelsChanged[*corev1.ConfigMap]}, base, generation) {
+		t.Error("expected an update to need every predicate to allow it")
+	}
+}
+
+func TestPredicatesFilterUpdates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newConfigMapServer()
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	var reconciles atomic.Int32
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(context.Context, *corev1.ConfigMap) error {
+		reconciles.Add(1)
+		return nil
+	}), &Options[*corev1.ConfigMap]{
+		Name:       "test",
+		Predicates: []Predicate[*corev1.ConfigMap]{GenerationChanged[*corev1.ConfigMap]},
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(ctx) }()
+	waitUntil(t, ctx, "initial reconcile", func() bool { return reconciles.Load() == 1 })
+
+	watch := <-server.watches
+	write := func(event string) {
+		t.Helper()
+		if _, err := io.WriteString(watch, event+"\n"); err != nil {
+			t.Fatalf("writing watch event failed: %v", err)
+		}
+	}
+	// A metadata-only update doesn't change the generation.
+	write(`{"type":"MODIFIED","object":{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"cm","namespace":"default","resourceVersion":"2","generation":3,"annotations":{"a":"b"}}}}`)
+	time.Sleep(200 * time.Millisecond)
+	if n := reconciles.Load(); n != 1 {
+		t.Fatalf("expected the update to be filtered, got %d reconciles", n)
+	}
+	write(`{"type":"MODIFIED","object":{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"cm","namespace":"default","resourceVersion":"3","generation":4}}}`)
+	waitUntil(t, ctx, "reconcile of the new generation", func() bool { return reconciles.Load() == 2 })
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+}
+
+func TestWatchOwnedPredicates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// ConfigMaps owned by Pods. The controller isn't run, so its Pods are
+	// never listed.
+	server := newConfigMapServer()
+	ctrl := New(generic.NewClientGVR[*corev1.Pod](schema.GroupVersionResource{Version: "v1", Resource: "pods"}, server.config()),
+		ReconcilerFunc[*corev1.Pod](func(context.Context, *corev1.Pod) error { return nil }), nil)
+	defer ctrl.shutdown()
+
+	configMaps := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, server.config())
+	if _, err := WatchOwned(ctx, ctrl, configMaps, false,
+		LabelsChanged[*corev1.ConfigMap]); err != nil {
+		t.Fatalf("WatchOwned failed: %v", err)
+	}
+
+	watch := <-server.watches
+	write := func(event string) {
+		t.Helper()
+		if _, err := io.WriteString(watch, event+"\n"); err != nil {
+			t.Fatalf("writing watch event failed: %v", err)
+		}
+	}
+	const ownerRefs = `"ownerReferences":[{"apiVersion":"v1","kind":"Pod","name":"owner","uid":"1"}]`
+	write(`{"type":"MODIFIED","object":{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"cm","namespace":"default","resourceVersion":"2",` + ownerRefs + `}}}`)
+	time.Sleep(200 * time.Millisecond)
+	if n := ctrl.queue.Len(); n != 0 {
+		t.Fatalf("expected the update to be filtered, got %d queued keys", n)
+	}
+	write(`{"type":"MODIFIED","object":{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"cm","namespace":"default","resourceVersion":"3","labels":{"app":"a"},` + ownerRefs + `}}}`)
+	waitUntil(t, ctx, "the owner to be enqueued", func() bool { return ctrl.queue.Len() > 0 })
+	if key, _ := ctrl.queue.Get(); key != "default/owner" {
+		t.Errorf("expected default/owner to be enqueued, got %q", key)
+	}
+}
@@ -20,6 +20,20 @@ type Reconciler[T runtime.Object] interface {
 	Reconcile(ctx context.Context, obj T) error
 }
 
+// DeletionReconciler is an optional interface for Reconcilers that need to
+// act when an object is deleted, such as to clean up external state that
+// isn't protected by a finalizer.
+//
+// If a Reconciler implements DeletionReconciler, ReconcileDeleted is called
+// instead of Reconcile when the object for a queued key no longer exists.
+// The key is in namespace/name form (or just name, for cluster-scoped
+// resources). Errors are retried like errors from Reconcile.
+//
+// Without a DeletionReconciler, keys for deleted objects are dropped.
+type DeletionReconciler[T runtime.Object] interface {
+	ReconcileDeleted(ctx context.Context, key string) error
+}
+
 // ReconcilerFunc is an adapter to allow ordinary functions to be used as Reconcilers.
 // If f is a function with the appropriate signature, ReconcilerFunc[T](f) is a
 // Reconciler[T] that calls f.
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of spans created by a Controller.
+const tracerName = "github.com/imjasonh/client-go2/controller"
+
+// startItemSpan starts the span covering the processing of key.
+func (c *Controller[T]) startItemSpan(ctx context.Context, key, namespace string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, "reconcile "+c.name, trace.WithAttributes(
+		attribute.String("controller.name", c.name),
+		attribute.String("k8s.object.key", key),
+		attribute.String("k8s.namespace.name", namespace),
+	))
+}
+
+// endItemSpan records the outcome of processing an item on span and ends it.
+func endItemSpan(span trace.Span, err error) {
+	span.SetAttributes(attribute.String("controller.outcome", string(outcomeFor(err))))
+	if err != nil && !IsRequeueError(err) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
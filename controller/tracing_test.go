@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/imjasonh/client-go2/generic"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestProcessItemTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, newConfigMapServer().config())
+
+	var reconcileSpan trace.SpanContext
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(ctx context.Context, cm *corev1.ConfigMap) error {
+		reconcileSpan = trace.SpanContextFromContext(ctx)
+		return nil
+	}), &Options[*corev1.ConfigMap]{Name: "test", TracerProvider: tp})
+
+	if err := ctrl.processItem(context.Background(), "default/cm"); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	get, item := spans[0], spans[1]
+	if item.Name != "reconcile test" {
+		t.Errorf("expected item span name %q, got %q", "reconcile test", item.Name)
+	}
+	if !reconcileSpan.IsValid() || reconcileSpan.SpanID() != item.SpanContext.SpanID() {
+		t.Errorf("expected Reconcile context to carry the item span, got %v", reconcileSpan.SpanID())
+	}
+	if get.Name != "get configmaps" {
+		t.Errorf("expected client span name %q, got %q", "get configmaps", get.Name)
+	}
+	if get.Parent.SpanID() != item.SpanContext.SpanID() {
+		t.Errorf("expected client span to be a child of the item span")
+	}
+
+	attrs := attribute.NewSet(item.Attributes...)
+	for k, want := range map[attribute.Key]attribute.Value{
+		"k8s.object.key":        attribute.StringValue("default/cm"),
+		"k8s.namespace.name":    attribute.StringValue("default"),
+		"k8s.object.generation": attribute.Int64Value(3),
+		"controller.outcome":    attribute.StringValue(string(OutcomeSuccess)),
+	} {
+		if got, _ := attrs.Value(k); got != want {
+			t.Errorf("attribute %s: expected %v, got %v", k, want.Emit(), got.Emit())
+		}
+	}
+}
+
+func TestProcessItemTracingError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	client := generic.NewClientGVR[*corev1.ConfigMap](schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, newConfigMapServer().config())
+
+	ctrl := New(client, ReconcilerFunc[*corev1.ConfigMap](func(ctx context.Context, cm *corev1.ConfigMap) error {
+		return errors.New("reconcile failed")
+	}), &Options[*corev1.ConfigMap]{Name: "test", TracerProvider: tp})
+
+	if err := ctrl.processItem(context.Background(), "default/cm"); err == nil {
+		t.Fatal("expected processItem to fail")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	item := spans[1]
+	if item.Status.Code != codes.Error {
+		t.Errorf("expected item span status Error, got %v", item.Status.Code)
+	}
+	if len(item.Events) == 0 {
+		t.Error("expected the error to be recorded on the item span")
+	}
+}
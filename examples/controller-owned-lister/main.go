@@ -104,14 +104,14 @@ func main() {
 	}
 
 	// Start a Secret informer to get the lister
-	secretLister, err := secretClient.Inform(ctx, generic.InformerHandler[*corev1.Secret]{
+	secretInformer, err := secretClient.Inform(ctx, generic.InformerHandler[*corev1.Secret]{
 		// Empty handler - we just want the lister for cache-backed operations
 	}, nil)
 	if err != nil {
 		log.Fatalf("Failed to start Secret informer: %v", err)
 	}
 
-	reconciler.secretLister = secretLister
+	reconciler.secretLister = secretInformer.Lister()
 
 	log.Println("Controller is running with secret lister available")
 
@@ -0,0 +1,287 @@
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// defaultWriteWaitTimeout is how long writes wait for the cache to observe
+// them if CachedClientOptions.WaitTimeout is not set.
+const defaultWriteWaitTimeout = 10 * time.Second
+
+// CachedClientOptions configures a CachedClient.
+type CachedClientOptions struct {
+	// WaitForWrites makes Create, Update, UpdateStatus and Patch block until
+	// the cache holds the written resourceVersion (or newer), and Delete
+	// block until the object is gone from the cache or marked for deletion,
+	// so that reads after a write observe it.
+	WaitForWrites bool
+	// WaitTimeout bounds how long writes wait for the cache. If the cache
+	// does not catch up in time the write still succeeds, and subsequent
+	// reads may be stale. The default is 10 seconds.
+	WaitTimeout time.Duration
+}
+
+// CachedClient serves reads of objects of type T from an informer's cache
+// and sends writes to the API server.
+//
+// Get and List read from the cache once the informer has synced. They read
+// from the API server instead before then, for namespaces the informer does
+// not watch, when the informer only caches objects matching a selector, and
+// when the request asks for something the cache can't answer, such as a
+// specific resourceVersion or a paginated list.
+//
+// Objects returned from the cache are deep copies, so callers may modify them.
+//
+//	informer, err := client.Inform(ctx, generic.InformerHandler[*corev1.Pod]{}, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	cached := generic.NewCachedClient(client, informer, &generic.CachedClientOptions{WaitForWrites: true})
+//	pod, err := cached.Get(ctx, "default", "my-pod", nil) // no API request
+type CachedClient[T runtime.Object] struct {
+	client   Client[T]
+	informer *Informer[T]
+	lister   *Lister[T]
+	opts     CachedClientOptions
+}
+
+// NewCachedClient returns a CachedClient that reads from informer and writes
+// with client. The informer must be for the same resource as client, and
+// may come from Inform or an InformerFactory.
+func NewCachedClient[T runtime.Object](client Client[T], informer *Informer[T], opts *CachedClientOptions) *CachedClient[T] {
+	c := &CachedClient[T]{
+		client:   client,
+		informer: informer,
+		lister:   informer.Lister(),
+	}
+	if opts != nil {
+		c.opts = *opts
+	}
+	if c.opts.WaitTimeout == 0 {
+		c.opts.WaitTimeout = defaultWriteWaitTimeout
+	}
+	return c
+}
+
+// Live returns the underlying client, which always reads from the API server.
+func (c *CachedClient[T]) Live() Client[T] {
+	return c.client
+}
+
+// Lister returns a Lister for the client's cache.
+func (c *CachedClient[T]) Lister() *Lister[T] {
+	return c.lister
+}
+
+// cacheCovers returns true if reads in namespace can be served from the cache.
+func (c *CachedClient[T]) cacheCovers(namespace string) bool {
+	if c.informer.selective || !c.informer.HasSynced() {
+		return false
+	}
+	return c.informer.namespace == "" || c.informer.namespace == namespace
+}
+
+// Get returns the named object from the cache, or from the API server if the
+// cache can't serve it. See CachedClient.
+func (c *CachedClient[T]) Get(ctx context.Context, namespace, name string, opts *metav1.GetOptions) (T, error) {
+	if !c.cacheCovers(namespace) || (opts != nil && opts.ResourceVersion != "" && opts.ResourceVersion != "0") {
+		return c.client.Get(ctx, namespace, name, opts)
+	}
+	var t T
+	var err error
+	if namespace == "" {
+		t, err = c.lister.Get(name)
+	} else {
+		t, err = c.lister.ByNamespace(namespace).Get(name)
+	}
+	if err != nil {
+		return t, err
+	}
+	return t.DeepCopyObject().(T), nil
+}
+
+// List returns the matching objects from the cache, or from the API server
+// if the cache can't serve them. See CachedClient.
+//
+// Field selectors are evaluated against the cached objects with
+// FieldSelector, so any field path may be used when reading from the cache.
+func (c *CachedClient[T]) List(ctx context.Context, namespace string, opts *metav1.ListOptions) ([]T, error) {
+	if !c.cacheCovers(namespace) || (opts != nil && (opts.Limit != 0 || opts.Continue != "" ||
+		(opts.ResourceVersion != "" && opts.ResourceVersion != "0"))) {
+		return c.client.List(ctx, namespace, opts)
+	}
+	if opts == nil {
+		opts = &metav1.ListOptions{}
+	}
+	sel, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid label selector %q: %v", opts.LabelSelector, err))
+	}
+	match, err := FieldSelector[T](opts.FieldSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid field selector %q: %v", opts.FieldSelector, err))
+	}
+	var objs []T
+	if namespace == "" {
+		objs, err = c.lister.List(sel)
+	} else {
+		objs, err = c.lister.ByNamespace(namespace).List(sel)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []T
+	for _, obj := range objs {
+		if match(obj) {
+			out = append(out, obj.DeepCopyObject().(T))
+		}
+	}
+	return out, nil
+}
+
+// Watch watches objects on the API server.
+func (c *CachedClient[T]) Watch(ctx context.Context, namespace string, opts *metav1.ListOptions) (watch.Interface, error) {
+	return c.client.Watch(ctx, namespace, opts)
+}
+
+// Create creates an object on the API server.
+func (c *CachedClient[T]) Create(ctx context.Context, namespace string, t T, opts *metav1.CreateOptions) (T, error) {
+	result, err := c.client.Create(ctx, namespace, t, opts)
+	if err != nil {
+		return result, err
+	}
+	c.waitForWrite(ctx, result)
+	return result, nil
+}
+
+// Update updates an object on the API server.
+func (c *CachedClient[T]) Update(ctx context.Context, namespace string, t T, opts *metav1.UpdateOptions) (T, error) {
+	result, err := c.client.Update(ctx, namespace, t, opts)
+	if err != nil {
+		return result, err
+	}
+	c.waitForWrite(ctx, result)
+	return result, nil
+}
+
+// UpdateStatus updates the status subresource of an object on the API server.
+func (c *CachedClient[T]) UpdateStatus(ctx context.Context, namespace string, t T, opts *metav1.UpdateOptions) (T, error) {
+	result, err := c.client.UpdateStatus(ctx, namespace, t, opts)
+	if err != nil {
+		return result, err
+	}
+	c.waitForWrite(ctx, result)
+	return result, nil
+}
+
+// Patch patches an object on the API server.
+func (c *CachedClient[T]) Patch(ctx context.Context, namespace, name string, pt types.PatchType, data []byte, opts *metav1.PatchOptions) error {
+	body, err := c.client.patch(ctx, namespace, name, pt, data, opts)
+	if err != nil {
+		return err
+	}
+	if c.shouldWait() {
+		var result T
+		if err := json.Unmarshal(body, &result); err == nil {
+			c.waitForWrite(ctx, result)
+		}
+	}
+	return nil
+}
+
+// Delete deletes an object on the API server.
+func (c *CachedClient[T]) Delete(ctx context.Context, namespace, name string, opts *metav1.DeleteOptions) error {
+	if err := c.client.Delete(ctx, namespace, name, opts); err != nil {
+		return err
+	}
+	if c.shouldWait() && c.cacheCovers(namespace) {
+		c.poll(ctx, func() bool {
+			obj, exists, err := c.informer.informer.GetIndexer().GetByKey(objectKey(namespace, name))
+			if err != nil || !exists {
+				return true
+			}
+			m, err := meta.Accessor(obj)
+			return err != nil || m.GetDeletionTimestamp() != nil
+		})
+	}
+	return nil
+}
+
+// DeleteCollection deletes a collection of objects on the API server. It
+// does not wait for the cache.
+func (c *CachedClient[T]) DeleteCollection(ctx context.Context, namespace string, opts *metav1.DeleteOptions, listOpts *metav1.ListOptions) error {
+	return c.client.DeleteCollection(ctx, namespace, opts, listOpts)
+}
+
+// shouldWait returns true if writes should wait for the cache to observe them.
+func (c *CachedClient[T]) shouldWait() bool {
+	return c.opts.WaitForWrites && !c.client.IsDryRun()
+}
+
+// waitForWrite waits, if configured to, until the cache holds written at its
+// resourceVersion or newer.
+func (c *CachedClient[T]) waitForWrite(ctx context.Context, written T) {
+	if !c.shouldWait() {
+		return
+	}
+	m, err := meta.Accessor(written)
+	if err != nil || !c.cacheCovers(m.GetNamespace()) {
+		return
+	}
+	key := objectKey(m.GetNamespace(), m.GetName())
+	want := m.GetResourceVersion()
+	c.poll(ctx, func() bool {
+		obj, exists, err := c.informer.informer.GetIndexer().GetByKey(key)
+		if err != nil || !exists {
+			return false
+		}
+		cm, err := meta.Accessor(obj)
+		return err == nil && resourceVersionAtLeast(cm.GetResourceVersion(), want)
+	})
+}
+
+// poll calls done until it returns true, ctx is done or the wait times out.
+func (c *CachedClient[T]) poll(ctx context.Context, done func() bool) {
+	_ = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, c.opts.WaitTimeout, true, func(context.Context) (bool, error) {
+		return done(), nil
+	})
+}
+
+// objectKey returns the cache key of the named object.
+func objectKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// resourceVersionAtLeast returns true if resourceVersion got is the same as
+// or newer than want. Resource versions are opaque, but are integers in
+// practice; if either is not, only equal versions are considered newer.
+func resourceVersionAtLeast(got, want string) bool {
+	if got == want {
+		return true
+	}
+	g, err := strconv.ParseUint(got, 10, 64)
+	if err != nil {
+		return false
+	}
+	w, err := strconv.ParseUint(want, 10, 64)
+	if err != nil {
+		return false
+	}
+	return g >= w
+}
@@ -0,0 +1,190 @@
+package generic
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newCachedTestServer returns a mockTransport that serves a list of pods in
+// the default namespace, watches whose events are written by the test, and
+// single-object requests.
+func newCachedTestServer() *mockTransport {
+	return &mockTransport{
+		responses: map[string]mockResponse{
+			"GET /api/v1/namespaces/default/pods": {statusCode: http.StatusOK, body: pendingPodList},
+			"GET /api/v1/namespaces/other/pods/q": {statusCode: http.StatusOK, body: `{"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "q", "namespace": "other", "resourceVersion": "5"}}`},
+			"PUT":                                 {statusCode: http.StatusOK, body: updatedPod},
+			"PATCH":                               {statusCode: http.StatusOK, body: updatedPod},
+			"DELETE":                              {statusCode: http.StatusOK, body: `{"kind": "Status", "apiVersion": "v1", "status": "Success"}`},
+		},
+		watches: make(chan *io.PipeWriter, 10),
+	}
+}
+
+const updatedPod = `{"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "p", "namespace": "default", "resourceVersion": "2", "labels": {"app": "web"}}}`
+
+func TestCachedClientReads(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := newCachedTestServer()
+	client := newWaitTestClient(server)
+	informer, err := client.Inform(ctx, InformerHandler[*corev1.Pod]{}, &InformOptions[*corev1.Pod]{Namespace: "default"})
+	if err != nil {
+		t.Fatalf("Inform failed: %v", err)
+	}
+	server.takeRequests()
+	cached := NewCachedClient(client, informer, nil)
+
+	pod, err := cached.Get(ctx, "default", "p", nil)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if pod.ResourceVersion != "1" {
+		t.Errorf("Get returned resourceVersion %q, want 1", pod.ResourceVersion)
+	}
+	pod.Labels = map[string]string{"mutated": "true"}
+	if cachedPod, _ := informer.Lister().ByNamespace("default").Get("p"); cachedPod.Labels != nil {
+		t.Error("modifying the object returned by Get modified the cache")
+	}
+	if _, err := cached.Get(ctx, "default", "missing", nil); !apierrors.IsNotFound(err) {
+		t.Errorf("Get of missing object returned %v, want NotFound", err)
+	}
+	pods, err := cached.List(ctx, "default", &metav1.ListOptions{FieldSelector: "status.phase=Pending"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(pods) != 1 {
+		t.Errorf("List returned %d pods, want 1", len(pods))
+	}
+	if pods, err := cached.List(ctx, "default", &metav1.ListOptions{LabelSelector: "app=web"}); err != nil || len(pods) != 0 {
+		t.Errorf("List with unmatched selector = %d pods, %v; want none", len(pods), err)
+	}
+	if got := server.takeRequests(); len(got) != 0 {
+		t.Errorf("cached reads made API requests: %v", got)
+	}
+
+	// Reads the cache can't serve go to the API server.
+	if pod, err := cached.Get(ctx, "other", "q", nil); err != nil || pod.Name != "q" {
+		t.Errorf("Get in uncached namespace = %v, %v", pod, err)
+	}
+	if _, err := cached.Get(ctx, "default", "p", &metav1.GetOptions{ResourceVersion: "7"}); !apierrors.IsNotFound(err) {
+		t.Errorf("Get at resourceVersion returned %v, want NotFound from the server", err)
+	}
+	if _, err := cached.List(ctx, "default", &metav1.ListOptions{Limit: 10}); err != nil {
+		t.Errorf("paginated List failed: %v", err)
+	}
+	want := []string{"GET /api/v1/namespaces/other/pods/q", "GET /api/v1/namespaces/default/pods/p", "GET /api/v1/namespaces/default/pods"}
+	if got := server.takeRequests(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("live reads = %v, want %v", got, want)
+	}
+}
+
+func TestCachedClientUnsynced(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := newCachedTestServer()
+	client := newWaitTestClient(server)
+	cached := NewCachedClient(client, client.SharedInformer(NewInformerFactory(), nil), nil)
+
+	if _, err := cached.Get(ctx, "other", "q", nil); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got := server.takeRequests(); len(got) != 1 {
+		t.Errorf("expected Get before sync to read from the API server, got %v", got)
+	}
+}
+
+func TestCachedClientWaitForWrites(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := newCachedTestServer()
+	client := newWaitTestClient(server)
+	informer, err := client.Inform(ctx, InformerHandler[*corev1.Pod]{}, &InformOptions[*corev1.Pod]{Namespace: "default"})
+	if err != nil {
+		t.Fatalf("Inform failed: %v", err)
+	}
+	watch := <-server.watches
+	cached := NewCachedClient(client, informer, &CachedClientOptions{WaitForWrites: true})
+
+	pod, err := cached.Get(ctx, "default", "p", nil)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		io.WriteString(watch, `{"type": "MODIFIED", "object": {"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "p", "namespace": "default", "resourceVersion": "2", "labels": {"app": "web"}}}}`+"\n")
+	}()
+	if _, err := cached.Update(ctx, "default", pod, nil); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got, _ := cached.Get(ctx, "default", "p", nil); got.ResourceVersion != "2" {
+		t.Errorf("Get after Update returned resourceVersion %q, want 2", got.ResourceVersion)
+	}
+
+	// The cache is already at the patched version, so Patch returns at once.
+	start := time.Now()
+	if err := cached.Patch(ctx, "default", "p", types.MergePatchType, []byte(`{}`), nil); err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Patch waited %v for a version already in the cache", elapsed)
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		io.WriteString(watch, `{"type": "DELETED", "object": {"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "p", "namespace": "default", "resourceVersion": "3"}}}`+"\n")
+	}()
+	if err := cached.Delete(ctx, "default", "p", nil); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := cached.Get(ctx, "default", "p", nil); !apierrors.IsNotFound(err) {
+		t.Errorf("Get after Delete returned %v, want NotFound", err)
+	}
+}
+
+func TestCachedClientWaitTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := newCachedTestServer()
+	client := newWaitTestClient(server)
+	informer, err := client.Inform(ctx, InformerHandler[*corev1.Pod]{}, &InformOptions[*corev1.Pod]{Namespace: "default"})
+	if err != nil {
+		t.Fatalf("Inform failed: %v", err)
+	}
+	cached := NewCachedClient(client, informer, &CachedClientOptions{WaitForWrites: true, WaitTimeout: 100 * time.Millisecond})
+
+	pod, _ := cached.Get(ctx, "default", "p", nil)
+	result, err := cached.Update(ctx, "default", pod, nil)
+	if err != nil {
+		t.Fatalf("Update failed when the cache did not catch up: %v", err)
+	}
+	if result.ResourceVersion != "2" {
+		t.Errorf("Update returned resourceVersion %q, want 2", result.ResourceVersion)
+	}
+}
+
+func TestResourceVersionAtLeast(t *testing.T) {
+	for _, tt := range []struct {
+		got, want string
+		ok        bool
+	}{
+		{"2", "2", true},
+		{"10", "9", true},
+		{"9", "10", false},
+		{"abc", "abc", true},
+		{"abc", "1", false},
+	} {
+		if ok := resourceVersionAtLeast(tt.got, tt.want); ok != tt.ok {
+			t.Errorf("resourceVersionAtLeast(%q, %q) = %t, want %t", tt.got, tt.want, ok, tt.ok)
+		}
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"time"
 	"unicode"
 
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -64,6 +66,9 @@ func NewClientGVR[T runtime.Object](gvr schema.GroupVersionResource, config *res
 		}
 	}
 
+	// Record how long requests are throttled by the client-side rate limiter
+	configCopy.RateLimiter = rateLimiterFor(configCopy)
+
 	// Use the standard Kubernetes codecs for serialization
 	if configCopy.NegotiatedSerializer == nil {
 		configCopy.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
@@ -82,13 +87,38 @@ func NewClientGVR[T runtime.Object](gvr schema.GroupVersionResource, config *res
 // inferGVR attempts to determine the GroupVersionResource for a given type T
 // by using the Kubernetes scheme and discovery client.
 func inferGVR[T runtime.Object](config *rest.Config) (schema.GroupVersionResource, error) {
+	gvk, err := gvkFor[T]()
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+
+	// Create a discovery client to get the REST mapping
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	// Get the API group resources
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("failed to get API group resources: %w", err)
+	}
+
+	// Create a REST mapper
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+	return gvrFor(mapper, gvk)
+}
+
+// gvkFor returns the GroupVersionKind registered in the Kubernetes scheme
+// for type T.
+func gvkFor[T runtime.Object]() (schema.GroupVersionKind, error) {
 	// Create a zero-value instance of T to inspect
 	var zero T
 	typ := reflect.TypeOf(zero)
 
 	// Require pointer types - Kubernetes objects should always be pointers
 	if typ.Kind() != reflect.Ptr {
-		return schema.GroupVersionResource{}, fmt.Errorf("type %T must be a pointer type (e.g., *corev1.Pod, not corev1.Pod)", zero)
+		return schema.GroupVersionKind{}, fmt.Errorf("type %T must be a pointer type (e.g., *corev1.Pod, not corev1.Pod)", zero)
 	}
 
 	typ = typ.Elem()
@@ -98,53 +128,44 @@ func inferGVR[T runtime.Object](config *rest.Config) (schema.GroupVersionResourc
 	// Try to convert to runtime.Object
 	obj, ok := instance.(runtime.Object)
 	if !ok {
-		return schema.GroupVersionResource{}, fmt.Errorf("type %T does not implement runtime.Object", instance)
+		return schema.GroupVersionKind{}, fmt.Errorf("type %T does not implement runtime.Object", instance)
 	}
 
 	// Get the GVKs for this object from the scheme
 	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
 	if err != nil {
-		return schema.GroupVersionResource{}, fmt.Errorf("failed to get GVK for type %T: %w", zero, err)
+		return schema.GroupVersionKind{}, fmt.Errorf("failed to get GVK for type %T: %w", zero, err)
 	}
 
 	if len(gvks) == 0 {
-		return schema.GroupVersionResource{}, fmt.Errorf("no GVK registered for type %T", zero)
+		return schema.GroupVersionKind{}, fmt.Errorf("no GVK registered for type %T", zero)
 	}
 
 	// If multiple match, return an error.
 	if len(gvks) > 1 {
-		return schema.GroupVersionResource{}, fmt.Errorf("multiple GVKs registered for type %T: %v", zero, gvks)
-	}
-	gvk := gvks[0]
-
-	// Create a discovery client to get the REST mapping
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
-	if err != nil {
-		return schema.GroupVersionResource{}, fmt.Errorf("failed to create discovery client: %w", err)
-	}
-
-	// Get the API group resources
-	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
-	if err != nil {
-		return schema.GroupVersionResource{}, fmt.Errorf("failed to get API group resources: %w", err)
+		return schema.GroupVersionKind{}, fmt.Errorf("multiple GVKs registered for type %T: %v", zero, gvks)
 	}
+	return gvks[0], nil
+}
 
-	// Create a REST mapper
-	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
-
-	// Get the resource mapping for the GVK
+// gvrFor maps gvk to its GroupVersionResource with mapper.
+func gvrFor(mapper meta.RESTMapper, gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
 	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
 		return schema.GroupVersionResource{}, fmt.Errorf("failed to get REST mapping for %v: %w", gvk, err)
 	}
-
 	return mapping.Resource, nil
 }
 
 // Client is a generic Kubernetes client for a specific type T.
 type Client[T runtime.Object] struct {
-	gvr        schema.GroupVersionResource
-	restClient *rest.RESTClient
+	gvr            schema.GroupVersionResource
+	restClient     *rest.RESTClient
+	metrics        Metrics
+	tracerProvider trace.TracerProvider
+	dryRun         bool
+	metadataOnly   bool
+	tableObjects   bool
 }
 
 // isCRD returns true if this client was configured for a CRD (non-empty group)
@@ -166,6 +187,11 @@ func (c Client[T]) resourcePath(namespace string) string {
 	return path + "/" + c.gvr.Resource
 }
 
+// GVR returns the GroupVersionResource for this client.
+func (c Client[T]) GVR() schema.GroupVersionResource {
+	return c.gvr
+}
+
 // GVK returns the GroupVersionKind for this client.
 // Note: This is an approximation since we only have GVR. The Kind is derived
 // from the resource name by capitalizing and singularizing it.
@@ -222,12 +248,8 @@ func (c Client[T]) List(ctx context.Context, namespace string, opts *metav1.List
 		opts = &metav1.ListOptions{}
 	}
 	// Get raw response body
-	body, err := c.restClient.Get().
-		NamespaceIfScoped(namespace, namespace != "").
-		Resource(c.gvr.Resource).
-		VersionedParams(opts, scheme.ParameterCodec).
-		Do(ctx).
-		Raw()
+	req := c.collection(namespace, opts)
+	body, err := c.do(ctx, RequestInfo{Verb: "list", Namespace: namespace}, req).Raw()
 	if err != nil {
 		return nil, err
 	}
@@ -256,27 +278,8 @@ func (c Client[T]) Get(ctx context.Context, namespace, name string, opts *metav1
 	if opts == nil {
 		opts = &metav1.GetOptions{}
 	}
-
-	var body []byte
-	var err error
-	if c.isCRD() {
-		// CRD: Use AbsPath
-		path := c.resourcePath(namespace) + "/" + name
-		body, err = c.restClient.Get().
-			AbsPath(path).
-			VersionedParams(opts, scheme.ParameterCodec).
-			Do(ctx).
-			Raw()
-	} else {
-		// Built-in: Use Resource()
-		body, err = c.restClient.Get().
-			NamespaceIfScoped(namespace, namespace != "").
-			Resource(c.gvr.Resource).
-			Name(name).
-			VersionedParams(opts, scheme.ParameterCodec).
-			Do(ctx).
-			Raw()
-	}
+	req := c.object(namespace, name, opts)
+	body, err := c.do(ctx, RequestInfo{Verb: "get", Namespace: namespace, Name: name}, req).Raw()
 	if err != nil {
 		var zero T
 		return zero, err
@@ -295,13 +298,10 @@ func (c Client[T]) Create(ctx context.Context, namespace string, t T, opts *meta
 	if opts == nil {
 		opts = &metav1.CreateOptions{}
 	}
-	body, err := c.restClient.Post().
-		NamespaceIfScoped(namespace, namespace != "").
-		Resource(c.gvr.Resource).
+	req := c.at(c.restClient.Post(), namespace, "").
 		VersionedParams(opts, scheme.ParameterCodec).
-		Body(t).
-		Do(ctx).
-		Raw()
+		Body(t)
+	body, err := c.do(ctx, RequestInfo{Verb: "create", Namespace: namespace}, req).Raw()
 	if err != nil {
 		var zero T
 		return zero, err
@@ -339,14 +339,10 @@ func (c Client[T]) Update(ctx context.Context, namespace string, t T, opts *meta
 		}
 	}
 
-	body, err := c.restClient.Put().
-		NamespaceIfScoped(namespace, namespace != "").
-		Resource(c.gvr.Resource).
-		Name(meta.Name).
+	req := c.at(c.restClient.Put(), namespace, meta.Name).
 		VersionedParams(opts, scheme.ParameterCodec).
-		Body(t).
-		Do(ctx).
-		Raw()
+		Body(t)
+	body, err := c.do(ctx, RequestInfo{Verb: "update", Namespace: namespace, Name: meta.Name}, req).Raw()
 	if err != nil {
 		var zero T
 		return zero, err
@@ -365,29 +361,26 @@ func (c Client[T]) Delete(ctx context.Context, namespace, name string, opts *met
 	if opts == nil {
 		opts = &metav1.DeleteOptions{}
 	}
-	return c.restClient.Delete().
-		NamespaceIfScoped(namespace, namespace != "").
-		Resource(c.gvr.Resource).
-		Name(name).
-		VersionedParams(opts, scheme.ParameterCodec).
-		Do(ctx).
-		Error()
+	req := c.at(c.restClient.Delete(), namespace, name).
+		VersionedParams(opts, scheme.ParameterCodec)
+	return c.do(ctx, RequestInfo{Verb: "delete", Namespace: namespace, Name: name}, req).Error()
 }
 
 // Patch applies a patch to an object of type T in the specified namespace.
 func (c Client[T]) Patch(ctx context.Context, namespace, name string, pt types.PatchType, data []byte, opts *metav1.PatchOptions) error {
+	_, err := c.patch(ctx, namespace, name, pt, data, opts)
+	return err
+}
+
+// patch applies a patch and returns the raw patched object.
+func (c Client[T]) patch(ctx context.Context, namespace, name string, pt types.PatchType, data []byte, opts *metav1.PatchOptions) ([]byte, error) {
 	if opts == nil {
 		opts = &metav1.PatchOptions{}
 	}
-	_, err := c.restClient.Patch(pt).
-		NamespaceIfScoped(namespace, namespace != "").
-		Resource(c.gvr.Resource).
-		Name(name).
+	req := c.at(c.restClient.Patch(pt), namespace, name).
 		VersionedParams(opts, scheme.ParameterCodec).
-		Body(data).
-		Do(ctx).
-		Raw()
-	return err
+		Body(data)
+	return c.do(ctx, RequestInfo{Verb: "patch", Namespace: namespace, Name: name}, req).Raw()
 }
 
 // Watch returns a watch interface for watching changes to resources of type T.
@@ -396,11 +389,7 @@ func (c Client[T]) Watch(ctx context.Context, namespace string, opts *metav1.Lis
 		opts = &metav1.ListOptions{}
 	}
 	opts.Watch = true
-	return c.restClient.Get().
-		NamespaceIfScoped(namespace, namespace != "").
-		Resource(c.gvr.Resource).
-		VersionedParams(opts, scheme.ParameterCodec).
-		Watch(ctx)
+	return c.watch(ctx, RequestInfo{Verb: "watch", Namespace: namespace}, c.collection(namespace, opts))
 }
 
 // DeleteCollection deletes a collection of objects of type T.
@@ -411,13 +400,10 @@ func (c Client[T]) DeleteCollection(ctx context.Context, namespace string, opts
 	if listOpts == nil {
 		listOpts = &metav1.ListOptions{}
 	}
-	return c.restClient.Delete().
-		NamespaceIfScoped(namespace, namespace != "").
-		Resource(c.gvr.Resource).
+	req := c.at(c.restClient.Delete(), namespace, "").
 		VersionedParams(opts, scheme.ParameterCodec).
-		VersionedParams(listOpts, scheme.ParameterCodec).
-		Do(ctx).
-		Error()
+		VersionedParams(listOpts, scheme.ParameterCodec)
+	return c.do(ctx, RequestInfo{Verb: "deletecollection", Namespace: namespace}, req).Error()
 }
 
 // UpdateStatus updates the status subresource of an object of type T.
@@ -444,28 +430,25 @@ func (c Client[T]) UpdateStatus(ctx context.Context, namespace string, t T, opts
 		}
 	}
 
-	var body []byte
+	var req *rest.Request
 	if c.isCRD() {
 		// CRD: Use AbsPath for the full resource path
 		path := c.resourcePath(namespace) + "/" + meta.Name + "/status"
-		body, err = c.restClient.Put().
+		req = c.restClient.Put().
 			AbsPath(path).
 			VersionedParams(opts, scheme.ParameterCodec).
-			Body(t).
-			Do(ctx).
-			Raw()
+			Body(t)
 	} else {
 		// Built-in: Use Resource()
-		body, err = c.restClient.Put().
+		req = c.restClient.Put().
 			NamespaceIfScoped(namespace, namespace != "").
 			Resource(c.gvr.Resource).
 			Name(meta.Name).
 			SubResource("status").
 			VersionedParams(opts, scheme.ParameterCodec).
-			Body(t).
-			Do(ctx).
-			Raw()
+			Body(t)
 	}
+	body, err := c.do(ctx, RequestInfo{Verb: "update", Subresource: "status", Namespace: namespace, Name: meta.Name}, req).Raw()
 	if err != nil {
 		var zero T
 		return zero, err
@@ -487,166 +470,242 @@ type InformerHandler[T runtime.Object] struct {
 	OnUpdate func(key string, oldObj, newObj T)
 	// OnDelete is called when an object is deleted from the informer.
 	OnDelete func(key string, obj T)
+	// OnDeleteUnknown, if set, is called instead of OnDelete when the
+	// informer missed an object's deletion, e.g. during a watch gap, and only
+	// noticed it was gone when it relisted. obj is the last known state of
+	// the object, which may be stale. If OnDeleteUnknown is nil, OnDelete is
+	// called with the last known state instead.
+	OnDeleteUnknown func(key string, obj T)
 	// OnError is called when an error occurs in the informer.
 	OnError func(obj any, err error)
 }
 
 // InformOptions contains options for configuring an informer
-type InformOptions struct {
+type InformOptions[T runtime.Object] struct {
+	// Namespace limits the informer to a single namespace.
+	// If empty, objects in all namespaces are watched.
+	Namespace string
 	// ListOptions allows setting label selectors, field selectors, etc.
 	ListOptions metav1.ListOptions
 	// ResyncPeriod overrides the default resync period if set
 	ResyncPeriod *time.Duration
+	// Indexers adds named indexes to the informer's cache, which can be
+	// queried with Lister.ByIndex. See IndexByOwnerUID, IndexPodByNodeName
+	// and IndexByLabel for common indexes.
+	Indexers map[string]func(T) ([]string, error)
+	// NoWaitForSync makes Inform return as soon as the informer has
+	// started, rather than after it has synced. Use the returned Informer's
+	// HasSynced or WaitForSync to find out when its cache is populated.
+	NoWaitForSync bool
+	// Transform, if set, is applied to every object before it is stored in
+	// the informer's cache and passed to handlers. It can modify and return
+	// the object it is given. Use StripManagedFields to shrink the cache.
+	Transform func(T) (T, error)
 }
 
 // Inform starts an informer for the specified type T and calls the appropriate handler methods
 //
-// It returns a Lister[T] that can be used to list objects in the cache.
-func (c Client[T]) Inform(ctx context.Context, handler InformerHandler[T], opts *InformOptions) (*Lister[T], error) {
-	// Create a ListWatch using rest.Client with label selector support
-	lw := &cache.ListWatch{
-		ListFunc: func(listOpts metav1.ListOptions) (runtime.Object, error) {
-			// Merge provided options with runtime options
-			if opts != nil {
-				if opts.ListOptions.LabelSelector != "" {
-					listOpts.LabelSelector = opts.ListOptions.LabelSelector
-				}
-				if opts.ListOptions.FieldSelector != "" {
-					listOpts.FieldSelector = opts.ListOptions.FieldSelector
-				}
-			}
-			if c.isCRD() {
-				return c.restClient.Get().
-					AbsPath(c.resourcePath("")).
-					VersionedParams(&listOpts, scheme.ParameterCodec).
-					Do(ctx).
-					Get()
-			}
-			return c.restClient.Get().
-				Resource(c.gvr.Resource).
-				VersionedParams(&listOpts, scheme.ParameterCodec).
-				Do(ctx).
-				Get()
-		},
-		WatchFunc: func(watchOpts metav1.ListOptions) (watch.Interface, error) {
-			// Merge provided options with runtime options
-			if opts != nil {
-				if opts.ListOptions.LabelSelector != "" {
-					watchOpts.LabelSelector = opts.ListOptions.LabelSelector
-				}
-				if opts.ListOptions.FieldSelector != "" {
-					watchOpts.FieldSelector = opts.ListOptions.FieldSelector
-				}
-			}
-			if c.isCRD() {
-				return c.restClient.Get().
-					AbsPath(c.resourcePath("")).
-					VersionedParams(&watchOpts, scheme.ParameterCodec).
-					Watch(ctx)
-			}
-			return c.restClient.Get().
-				Resource(c.gvr.Resource).
-				VersionedParams(&watchOpts, scheme.ParameterCodec).
-				Watch(ctx)
-		},
+// By default Inform blocks until the informer has synced; set
+// InformOptions.NoWaitForSync to return immediately. The informer runs until
+// ctx is done or the returned Informer is stopped.
+func (c Client[T]) Inform(ctx context.Context, handler InformerHandler[T], opts *InformOptions[T]) (*Informer[T], error) {
+	informer := c.newInformer(opts)
+	if _, err := informer.AddEventHandler(handler.resourceEventHandler()); err != nil {
+		return nil, fmt.Errorf("failed to add event handler: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go informer.RunWithContext(ctx)
+	i := newInformerHandle(c, informer, opts, cancel)
+	if opts != nil && opts.NoWaitForSync {
+		return i, nil
+	}
+	if err := i.WaitForSync(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	return i, nil
+}
+
+// newInformer creates an informer for T configured by opts, without starting it.
+func (c Client[T]) newInformer(opts *InformOptions[T]) cache.SharedIndexInformer {
+	if opts == nil {
+		opts = &InformOptions[T]{}
 	}
 
 	// Set default resync period
 	resync := resyncPeriod
-	if opts != nil && opts.ResyncPeriod != nil {
+	if opts.ResyncPeriod != nil {
 		resync = *opts.ResyncPeriod
 	}
 
-	// Create a new informer
+	indexers := toIndexers(opts.Indexers)
+	indexers[cache.NamespaceIndex] = cache.MetaNamespaceIndexFunc
+
 	var zero T
-	informer := cache.NewSharedIndexInformer(lw, zero, resync, cache.Indexers{
-		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
-	})
+	informer := cache.NewSharedIndexInformer(c.listWatch(opts.Namespace, opts.ListOptions), zero, resync, indexers)
+	if transform := opts.Transform; transform != nil {
+		// This can only fail once the informer has started, and it hasn't.
+		_ = informer.SetTransform(func(obj any) (any, error) {
+			t, ok := obj.(T)
+			if !ok {
+				return obj, nil
+			}
+			return transform(t)
+		})
+	}
+	return informer
+}
+
+// listWatch returns a ListWatch for resources of type T in the given
+// namespace (or all namespaces if empty), applying the label and field
+// selectors from base to every list and watch call.
+func (c Client[T]) listWatch(namespace string, base metav1.ListOptions) *cache.ListWatch {
+	// Merge provided options with runtime options
+	merge := func(opts *metav1.ListOptions) {
+		if base.LabelSelector != "" {
+			opts.LabelSelector = base.LabelSelector
+		}
+		if base.FieldSelector != "" {
+			opts.FieldSelector = base.FieldSelector
+		}
+	}
+	return &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, listOpts metav1.ListOptions) (runtime.Object, error) {
+			merge(&listOpts)
+			return c.do(ctx, RequestInfo{Verb: "list", Namespace: namespace}, c.collection(namespace, &listOpts)).Get()
+		},
+		WatchFuncWithContext: func(ctx context.Context, watchOpts metav1.ListOptions) (watch.Interface, error) {
+			merge(&watchOpts)
+			watchOpts.Watch = true
+			return c.watch(ctx, RequestInfo{Verb: "watch", Namespace: namespace}, c.collection(namespace, &watchOpts))
+		},
+	}
+}
 
-	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+// collection returns a GET request for the collection of resources of type T
+// in the given namespace (or all namespaces if empty).
+func (c Client[T]) collection(namespace string, opts *metav1.ListOptions) *rest.Request {
+	return c.at(c.restClient.Get(), namespace, "").
+		VersionedParams(opts, scheme.ParameterCodec)
+}
+
+// object returns a GET request for the named resource of type T.
+func (c Client[T]) object(namespace, name string, opts *metav1.GetOptions) *rest.Request {
+	return c.at(c.restClient.Get(), namespace, name).
+		VersionedParams(opts, scheme.ParameterCodec)
+}
+
+// at points req at the named resource of type T, or at their collection if
+// name is empty.
+func (c Client[T]) at(req *rest.Request, namespace, name string) *rest.Request {
+	if c.isCRD() {
+		// CRD: Use AbsPath
+		path := c.resourcePath(namespace)
+		if name != "" {
+			path += "/" + name
+		}
+		return req.AbsPath(path)
+	}
+	// Built-in: Use Resource()
+	req = req.NamespaceIfScoped(namespace, namespace != "").
+		Resource(c.gvr.Resource)
+	if name != "" {
+		req = req.Name(name)
+	}
+	return req
+}
+
+// SubResource returns a request for a subresource of the given resource.
+// This can be used to access subresources like logs, exec, attach, etc.
+// For example, to get pod logs:
+//
+//	req := client.SubResource("default", "my-pod", "log")
+//	req.VersionedParams(&v1.PodLogOptions{...}, scheme.ParameterCodec)
+//
+// The caller executes the request, so unlike the client's other methods it
+// is not reported to the client's Metrics, traced, or made a dry run.
+func (c Client[T]) SubResource(namespace, name, subresource string) *rest.Request {
+	return c.restClient.Get().
+		NamespaceIfScoped(namespace, namespace != "").
+		Name(name).
+		Resource(c.gvr.Resource).
+		SubResource(subresource)
+}
+
+// RESTClient returns the underlying rest.RESTClient.
+// This is useful for advanced use cases where direct access to the REST client is needed.
+func (c Client[T]) RESTClient() *rest.RESTClient {
+	return c.restClient
+}
+
+// resourceEventHandler adapts h to a cache.ResourceEventHandler, converting
+// objects to T and computing their keys.
+func (h InformerHandler[T]) resourceEventHandler() cache.ResourceEventHandler {
+	var zero T
+	return cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
-			if handler.OnAdd == nil {
+			if h.OnAdd == nil {
 				return
 			}
 			key, err := cache.MetaNamespaceKeyFunc(obj)
 			if err != nil {
-				handler.handleErr(obj, err)
+				h.handleErr(obj, err)
 				return
 			}
 			t, ok := obj.(T)
 			if !ok {
-				handler.handleErr(obj, fmt.Errorf("expected type %T, got %T", zero, obj))
+				h.handleErr(obj, fmt.Errorf("expected type %T, got %T", zero, obj))
 				return
 			}
-			handler.OnAdd(key, t)
+			h.OnAdd(key, t)
 		},
 		UpdateFunc: func(oldObj, newObj any) {
-			if handler.OnUpdate == nil {
+			if h.OnUpdate == nil {
 				return
 			}
 			key, err := cache.MetaNamespaceKeyFunc(newObj)
 			if err != nil {
-				handler.handleErr(newObj, err)
+				h.handleErr(newObj, err)
 				return
 			}
 			oldT, ok := oldObj.(T)
 			if !ok {
-				handler.handleErr(oldObj, fmt.Errorf("failed to cast old object to expected type: %v", oldObj))
+				h.handleErr(oldObj, fmt.Errorf("failed to cast old object to expected type: %v", oldObj))
 				return
 			}
 			newT, ok := newObj.(T)
 			if !ok {
-				handler.handleErr(newObj, fmt.Errorf("failed to cast new object to expected type: %v", newObj))
+				h.handleErr(newObj, fmt.Errorf("failed to cast new object to expected type: %v", newObj))
 				return
 			}
-			handler.OnUpdate(key, oldT, newT)
+			h.OnUpdate(key, oldT, newT)
 		},
 		DeleteFunc: func(obj any) {
-			if handler.OnDelete == nil {
+			onDelete := h.OnDelete
+			// Unwrap the last known state of objects whose deletion was missed.
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+				if h.OnDeleteUnknown != nil {
+					onDelete = h.OnDeleteUnknown
+				}
+			}
+			if onDelete == nil {
 				return
 			}
 			key, err := cache.MetaNamespaceKeyFunc(obj)
 			if err != nil {
-				handler.handleErr(obj, fmt.Errorf("failed to get key for deleted object: %v", obj))
+				h.handleErr(obj, fmt.Errorf("failed to get key for deleted object: %v", obj))
 				return
 			}
 			objT, ok := obj.(T)
 			if !ok {
-				handler.handleErr(obj, fmt.Errorf("failed to cast deleted object to expected type: %v", obj))
+				h.handleErr(obj, fmt.Errorf("failed to cast deleted object to expected type: %v", obj))
 				return
 			}
-			handler.OnDelete(key, objT)
+			onDelete(key, objT)
 		},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to add event handler: %w", err)
-	}
-
-	go informer.Run(ctx.Done())
-	if !cache.WaitForNamedCacheSync(c.gvr.String(), ctx.Done(), informer.HasSynced) {
-		return nil, fmt.Errorf("failed to sync informer for %s", c.gvr.String())
 	}
-	return NewLister[T](informer, c.gvr.GroupResource()), nil
-}
-
-// SubResource returns a request for a subresource of the given resource.
-// This can be used to access subresources like logs, exec, attach, etc.
-// For example, to get pod logs:
-//
-//	req := client.SubResource("default", "my-pod", "log")
-//	req.VersionedParams(&v1.PodLogOptions{...}, scheme.ParameterCodec)
-func (c Client[T]) SubResource(namespace, name, subresource string) *rest.Request {
-	return c.restClient.Get().
-		NamespaceIfScoped(namespace, namespace != "").
-		Name(name).
-		Resource(c.gvr.Resource).
-		SubResource(subresource)
-}
-
-// RESTClient returns the underlying rest.RESTClient.
-// This is useful for advanced use cases where direct access to the REST client is needed.
-func (c Client[T]) RESTClient() *rest.RESTClient {
-	return c.restClient
 }
 
 func (h InformerHandler[T]) handleErr(obj any, err error) {
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -17,9 +19,18 @@ import (
 	"k8s.io/client-go/rest"
 )
 
-// mockTransport implements http.RoundTripper for testing
+// mockTransport implements http.RoundTripper for testing. Responses are
+// keyed by "METHOD path?query", falling back to "METHOD path" and then to
+// "METHOD" alone, which matches any path. Watch requests use the method
+// WATCH, or match a "GET path?query" response exactly; if no response
+// matches one, it is sent a pipe on watches, through which the test writes
+// events. Every request is recorded.
 type mockTransport struct {
 	responses map[string]mockResponse
+	watches   chan *io.PipeWriter
+
+	mu   sync.Mutex
+	reqs []*http.Request
 }
 
 type mockResponse struct {
@@ -28,32 +39,73 @@ type mockResponse struct {
 }
 
 func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	key := req.Method + " " + req.URL.Path
-	if req.URL.RawQuery != "" {
-		key += "?" + req.URL.RawQuery
-	}
-	if resp, ok := m.responses[key]; ok {
-		return &http.Response{
-			StatusCode: resp.statusCode,
-			Body:       io.NopCloser(strings.NewReader(resp.body)),
-			Header:     make(http.Header),
-		}, nil
-	}
-	// Try without query params for debugging
-	keyWithoutQuery := req.Method + " " + req.URL.Path
-	if resp, ok := m.responses[keyWithoutQuery]; ok && req.URL.RawQuery != "" {
-		// Log that we're using the key without query params
-		return &http.Response{
-			StatusCode: resp.statusCode,
-			Body:       io.NopCloser(strings.NewReader(resp.body)),
-			Header:     make(http.Header),
-		}, nil
-	}
+	m.mu.Lock()
+	m.reqs = append(m.reqs, req)
+	m.mu.Unlock()
+
+	method, path := req.Method, req.URL.Path
+	keys := []string{method + " " + path + "?" + req.URL.RawQuery, method + " " + path, method}
+	if req.URL.Query().Get("watch") == "true" {
+		method = "WATCH"
+		keys = append([]string{method + " " + path + "?" + req.URL.RawQuery, method + " " + path, method}, keys[0])
+	}
+	for _, key := range keys {
+		if resp, ok := m.responses[key]; ok {
+			return m.respond(req, resp.statusCode, io.NopCloser(strings.NewReader(resp.body))), nil
+		}
+	}
+	if method == "WATCH" && m.watches != nil {
+		r, w := io.Pipe()
+		m.watches <- w
+		go func() {
+			<-req.Context().Done()
+			w.Close()
+		}()
+		return m.respond(req, http.StatusOK, r), nil
+	}
+	return m.respond(req, http.StatusNotFound, io.NopCloser(strings.NewReader(`{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"not found","reason":"NotFound","code":404}`))), nil
+}
+
+func (m *mockTransport) respond(req *http.Request, code int, body io.ReadCloser) *http.Response {
 	return &http.Response{
-		StatusCode: 404,
-		Body:       io.NopCloser(strings.NewReader(`{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"not found","code":404}`)),
-		Header:     make(http.Header),
-	}, nil
+		StatusCode: code,
+		Body:       body,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Request:    req,
+	}
+}
+
+// requests returns every request made so far.
+func (m *mockTransport) requests() []*http.Request {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.reqs)
+}
+
+// takeRequests returns the method and path of every request but watches
+// made since the last call.
+func (m *mockTransport) takeRequests() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []string
+	for _, req := range m.reqs {
+		if req.URL.Query().Get("watch") != "true" {
+			out = append(out, req.Method+" "+req.URL.Path)
+		}
+	}
+	m.reqs = nil
+	return out
+}
+
+// count returns the number of requests made with method to path.
+func (m *mockTransport) count(method, path string) int {
+	n := 0
+	for _, req := range m.requests() {
+		if req.Method == method && req.URL.Path == path {
+			n++
+		}
+	}
+	return n
 }
 
 func TestNewClientGVR(t *testing.T) {
@@ -517,6 +569,52 @@ func TestNewClientGVRCustomResource(t *testing.T) {
 	}
 }
 
+func TestCustomResourcePaths(t *testing.T) {
+	ctx := context.Background()
+	const path = "/apis/custom.io/v1/namespaces/default/myresources"
+	pod := `{"kind":"Pod","apiVersion":"v1","metadata":{"name":"r","namespace":"default"}}`
+	client := NewClientGVR[*corev1.Pod](
+		schema.GroupVersionResource{Group: "custom.io", Version: "v1", Resource: "myresources"},
+		&rest.Config{
+			Host: "http://localhost",
+			Transport: &mockTransport{
+				responses: map[string]mockResponse{
+					"GET " + path:           {statusCode: 200, body: `{"kind":"PodList","apiVersion":"v1","items":[` + pod + `]}`},
+					"GET " + path + "/r":    {statusCode: 200, body: pod},
+					"POST " + path:          {statusCode: 201, body: pod},
+					"PUT " + path + "/r":    {statusCode: 200, body: pod},
+					"PATCH " + path + "/r":  {statusCode: 200, body: pod},
+					"DELETE " + path + "/r": {statusCode: 200, body: `{"kind":"Status","apiVersion":"v1","status":"Success"}`},
+					"DELETE " + path:        {statusCode: 200, body: `{"kind":"Status","apiVersion":"v1","status":"Success"}`},
+				},
+			},
+		},
+	)
+
+	obj := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "r", Namespace: "default"}}
+	if _, err := client.List(ctx, "default", nil); err != nil {
+		t.Errorf("List failed: %v", err)
+	}
+	if _, err := client.Get(ctx, "default", "r", nil); err != nil {
+		t.Errorf("Get failed: %v", err)
+	}
+	if _, err := client.Create(ctx, "default", obj, nil); err != nil {
+		t.Errorf("Create failed: %v", err)
+	}
+	if _, err := client.Update(ctx, "default", obj, nil); err != nil {
+		t.Errorf("Update failed: %v", err)
+	}
+	if err := client.Patch(ctx, "default", "r", types.MergePatchType, []byte(`{}`), nil); err != nil {
+		t.Errorf("Patch failed: %v", err)
+	}
+	if err := client.Delete(ctx, "default", "r", nil); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if err := client.DeleteCollection(ctx, "default", nil, nil); err != nil {
+		t.Errorf("DeleteCollection failed: %v", err)
+	}
+}
+
 // TestListWithLabelSelector tests List with label selector
 func TestListWithLabelSelector(t *testing.T) {
 	ctx := context.Background()
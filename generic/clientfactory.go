@@ -0,0 +1,103 @@
+package generic
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// ClientFactory creates clients for many types from one config, sharing a
+// single discovery cache to infer their resources.
+//
+// NewClient runs discovery for every client it creates; a ClientFactory runs
+// it once, on first use, and again only when asked for a type the cached
+// discovery doesn't know about, such as a newly installed CRD.
+//
+//	clients, err := generic.NewClientFactory(config)
+//	if err != nil {
+//	    return err
+//	}
+//	pods, err := generic.ClientFor[*corev1.Pod](clients)
+//	deployments, err := generic.ClientFor[*appsv1.Deployment](clients)
+type ClientFactory struct {
+	config         *rest.Config
+	mapper         *restmapper.DeferredDiscoveryRESTMapper
+	metrics        Metrics
+	tracerProvider trace.TracerProvider
+}
+
+// NewClientFactory returns a ClientFactory for config. It does not contact
+// the API server.
+func NewClientFactory(config *rest.Config) (*ClientFactory, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	return &ClientFactory{
+		config: rest.CopyConfig(config),
+		mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+	}, nil
+}
+
+// WithMetrics makes every client created by f report its requests to m.
+// It returns f.
+func (f *ClientFactory) WithMetrics(m Metrics) *ClientFactory {
+	f.metrics = m
+	return f
+}
+
+// WithTracerProvider makes every client created by f trace its requests
+// with tp. It returns f.
+func (f *ClientFactory) WithTracerProvider(tp trace.TracerProvider) *ClientFactory {
+	f.tracerProvider = tp
+	return f
+}
+
+// Config returns a copy of the config clients are created with.
+func (f *ClientFactory) Config() *rest.Config {
+	return rest.CopyConfig(f.config)
+}
+
+// RESTMapper returns the factory's cached RESTMapper.
+func (f *ClientFactory) RESTMapper() meta.RESTMapper {
+	return f.mapper
+}
+
+// ClientFor returns a client for type T, inferring its resource like
+// NewClient but from f's discovery cache.
+func ClientFor[T runtime.Object](f *ClientFactory) (Client[T], error) {
+	gvk, err := gvkFor[T]()
+	if err != nil {
+		return Client[T]{}, err
+	}
+	gvr, err := gvrFor(f.mapper, gvk)
+	if meta.IsNoMatchError(err) {
+		// The type may have been installed since discovery was cached.
+		f.mapper.Reset()
+		gvr, err = gvrFor(f.mapper, gvk)
+	}
+	if err != nil {
+		return Client[T]{}, err
+	}
+	return ClientForGVR[T](f, gvr), nil
+}
+
+// ClientForGVR returns a client for type T and an explicit resource, like
+// NewClientGVR.
+func ClientForGVR[T runtime.Object](f *ClientFactory, gvr schema.GroupVersionResource) Client[T] {
+	c := NewClientGVR[T](gvr, f.config)
+	if f.metrics != nil {
+		c = c.WithMetrics(f.metrics)
+	}
+	if f.tracerProvider != nil {
+		c = c.WithTracerProvider(f.tracerProvider)
+	}
+	return c
+}
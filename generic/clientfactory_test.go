@@ -0,0 +1,62 @@
+package generic
+
+import (
+	"net/http"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestClientFactory(t *testing.T) {
+	transport := &mockTransport{responses: map[string]mockResponse{
+		"GET /api":  {statusCode: 200, body: `{"kind": "APIVersions", "versions": ["v1"]}`},
+		"GET /apis": {statusCode: 200, body: `{"kind": "APIGroupList", "groups": []}`},
+		"GET /api/v1": {statusCode: 200, body: `{"kind": "APIResourceList", "groupVersion": "v1", "resources": [
+				{"name": "pods", "singularName": "pod", "namespaced": true, "kind": "Pod", "verbs": ["get", "list"]},
+				{"name": "configmaps", "singularName": "configmap", "namespaced": true, "kind": "ConfigMap", "verbs": ["get", "list"]}
+			]}`},
+	}}
+	clients, err := NewClientFactory(&rest.Config{Host: "http://test", Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClientFactory failed: %v", err)
+	}
+	if reqs := transport.takeRequests(); len(reqs) != 0 {
+		t.Errorf("NewClientFactory made requests: %v", reqs)
+	}
+
+	pods, err := ClientFor[*corev1.Pod](clients)
+	if err != nil {
+		t.Fatalf("ClientFor Pod failed: %v", err)
+	}
+	if want := (schema.GroupVersionResource{Version: "v1", Resource: "pods"}); pods.GVR() != want {
+		t.Errorf("Pod GVR = %v, want %v", pods.GVR(), want)
+	}
+	configMaps, err := ClientFor[*corev1.ConfigMap](clients)
+	if err != nil {
+		t.Fatalf("ClientFor ConfigMap failed: %v", err)
+	}
+	if configMaps.GVR().Resource != "configmaps" {
+		t.Errorf("ConfigMap GVR = %v", configMaps.GVR())
+	}
+	if n := transport.count(http.MethodGet, "/api/v1"); n != 1 {
+		t.Errorf("expected discovery to run once for two clients, got %d", n)
+	}
+
+	// A type discovery doesn't know about refreshes discovery before failing.
+	if _, err := ClientFor[*appsv1.Deployment](clients); err == nil {
+		t.Error("ClientFor Deployment succeeded, want an error")
+	}
+	if n := transport.count(http.MethodGet, "/api/v1"); n != 2 {
+		t.Errorf("expected discovery to be refreshed for an unknown type, got %d runs", n)
+	}
+
+	// Clients share the factory's instrumentation.
+	m := &fakeMetrics{}
+	clients.WithMetrics(m)
+	if c := ClientForGVR[*corev1.Pod](clients, pods.GVR()); c.metrics != Metrics(m) {
+		t.Error("expected client to use the factory's metrics")
+	}
+}
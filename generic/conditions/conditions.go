@@ -0,0 +1,176 @@
+// Package conditions provides helpers for reading and writing
+// []metav1.Condition status conditions on any object type.
+//
+// Any type whose Status field (a struct or pointer to a struct) contains a
+// Conditions field of type []metav1.Condition is supported. The location of
+// the conditions is detected with reflection once per type and cached.
+//
+//	conditions.Set(widget, metav1.Condition{
+//	    Type:   "Ready",
+//	    Status: metav1.ConditionTrue,
+//	    Reason: "Reconciled",
+//	})
+//	if conditions.IsTrue(widget, "Ready") {
+//	    // ...
+//	}
+package conditions
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var conditionsType = reflect.TypeOf([]metav1.Condition(nil))
+
+// accessor describes where a type keeps its conditions.
+type accessor struct {
+	// status is the index of the Status field in the object struct.
+	status int
+	// statusPtr is true if the Status field is a pointer to a struct.
+	statusPtr bool
+	// conditions is the index of the Conditions field in the status struct.
+	conditions int
+}
+
+// accessors caches the accessor (or nil, if unsupported) for each type.
+var accessors sync.Map // map[reflect.Type]*accessor
+
+// accessorFor returns the cached accessor for typ, detecting it on first use.
+func accessorFor(typ reflect.Type) *accessor {
+	if a, ok := accessors.Load(typ); ok {
+		return a.(*accessor)
+	}
+	a := detect(typ)
+	accessors.Store(typ, a)
+	return a
+}
+
+// detect finds the Status.Conditions field of typ, which must be a pointer to a struct.
+func detect(typ reflect.Type) *accessor {
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	statusField, ok := typ.Elem().FieldByName("Status")
+	if !ok || len(statusField.Index) != 1 {
+		return nil
+	}
+	a := &accessor{status: statusField.Index[0]}
+	statusType := statusField.Type
+	if statusType.Kind() == reflect.Ptr {
+		a.statusPtr = true
+		statusType = statusType.Elem()
+	}
+	if statusType.Kind() != reflect.Struct {
+		return nil
+	}
+	condField, ok := statusType.FieldByName("Conditions")
+	if !ok || len(condField.Index) != 1 || condField.Type != conditionsType {
+		return nil
+	}
+	a.conditions = condField.Index[0]
+	return a
+}
+
+// conditionsField returns the addressable Conditions slice of obj.
+// If create is true, a nil Status pointer is allocated. It returns an error
+// if obj is nil.
+func conditionsField(obj runtime.Object, create bool) (reflect.Value, error) {
+	if obj == nil {
+		return reflect.Value{}, errors.New("object is nil")
+	}
+	v := reflect.ValueOf(obj)
+	a := accessorFor(v.Type())
+	if a == nil {
+		return reflect.Value{}, fmt.Errorf("type %T does not have Status.Conditions of type []metav1.Condition", obj)
+	}
+	if v.IsNil() {
+		return reflect.Value{}, fmt.Errorf("object of type %T is nil", obj)
+	}
+	status := v.Elem().Field(a.status)
+	if a.statusPtr {
+		if status.IsNil() {
+			if !create {
+				return reflect.Value{}, nil
+			}
+			status.Set(reflect.New(status.Type().Elem()))
+		}
+		status = status.Elem()
+	}
+	return status.Field(a.conditions), nil
+}
+
+// Supported returns true if objects of type T have Status.Conditions of type
+// []metav1.Condition.
+func Supported[T runtime.Object]() bool {
+	return accessorFor(reflect.TypeFor[T]()) != nil
+}
+
+// List returns the conditions of obj, or nil if it has none or its type is
+// not supported.
+func List[T runtime.Object](obj T) []metav1.Condition {
+	field, err := conditionsField(obj, false)
+	if err != nil || !field.IsValid() {
+		return nil
+	}
+	return field.Interface().([]metav1.Condition)
+}
+
+// Get returns the condition of the given type, or nil if it is not present.
+func Get[T runtime.Object](obj T, conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(List(obj), conditionType)
+}
+
+// IsTrue returns true if the condition of the given type is present and has
+// status True.
+func IsTrue[T runtime.Object](obj T, conditionType string) bool {
+	return meta.IsStatusConditionTrue(List(obj), conditionType)
+}
+
+// IsFalse returns true if the condition of the given type is present and has
+// status False.
+func IsFalse[T runtime.Object](obj T, conditionType string) bool {
+	return meta.IsStatusConditionFalse(List(obj), conditionType)
+}
+
+// Set adds or updates the condition with the type of cond.
+//
+// LastTransitionTime is set to now (unless cond provides one) when the
+// condition is added or its status changes, and is otherwise preserved.
+// If cond.ObservedGeneration is zero it is set to the object's
+// metadata.generation.
+//
+// Set returns true if the conditions were modified.
+func Set[T runtime.Object](obj T, cond metav1.Condition) (bool, error) {
+	field, err := conditionsField(obj, true)
+	if err != nil {
+		return false, err
+	}
+	if cond.ObservedGeneration == 0 {
+		if m, err := meta.Accessor(obj); err == nil {
+			cond.ObservedGeneration = m.GetGeneration()
+		}
+	}
+	conds := field.Interface().([]metav1.Condition)
+	changed := meta.SetStatusCondition(&conds, cond)
+	field.Set(reflect.ValueOf(conds))
+	return changed, nil
+}
+
+// Remove removes the condition of the given type.
+// It returns true if the condition was present.
+func Remove[T runtime.Object](obj T, conditionType string) (bool, error) {
+	field, err := conditionsField(obj, false)
+	if err != nil || !field.IsValid() {
+		return false, err
+	}
+	conds := field.Interface().([]metav1.Condition)
+	removed := meta.RemoveStatusCondition(&conds, conditionType)
+	field.Set(reflect.ValueOf(conds))
+	return removed, nil
+}
@@ -0,0 +1,233 @@
+package conditions
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imjasonh/client-go2/generic"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// widget is a CRD-style type with a pointer status.
+type widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status            *widgetStatus `json:"status,omitempty"`
+}
+
+type widgetStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+func (w *widget) DeepCopyObject() runtime.Object {
+	out := *w
+	return &out
+}
+
+func TestSupported(t *testing.T) {
+	if !Supported[*policyv1.PodDisruptionBudget]() {
+		t.Error("expected PodDisruptionBudget to be supported")
+	}
+	if !Supported[*widget]() {
+		t.Error("expected widget to be supported")
+	}
+	// Pods have conditions, but not []metav1.Condition.
+	if Supported[*corev1.Pod]() {
+		t.Error("expected Pod not to be supported")
+	}
+	if Supported[*corev1.ConfigMap]() {
+		t.Error("expected ConfigMap not to be supported")
+	}
+}
+
+func TestSetAndGet(t *testing.T) {
+	pdb := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+
+	changed, err := Set(pdb, metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Pending"})
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if !changed {
+		t.Error("expected adding a condition to report a change")
+	}
+
+	cond := Get(pdb, "Ready")
+	if cond == nil {
+		t.Fatal("expected Ready condition")
+	}
+	if cond.ObservedGeneration != 3 {
+		t.Errorf("expected observedGeneration 3, got %d", cond.ObservedGeneration)
+	}
+	if cond.LastTransitionTime.IsZero() {
+		t.Error("expected lastTransitionTime to be set")
+	}
+	if IsTrue(pdb, "Ready") || !IsFalse(pdb, "Ready") {
+		t.Error("expected Ready to be False")
+	}
+}
+
+func TestSetLastTransitionTime(t *testing.T) {
+	before := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	w := &widget{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+	if _, err := Set(w, metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Pending", LastTransitionTime: before}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if w.Status == nil {
+		t.Fatal("expected status to be allocated")
+	}
+
+	// Same status, new reason: transition time is preserved.
+	w.Generation = 2
+	if _, err := Set(w, metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "StillPending"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	cond := Get(w, "Ready")
+	if !cond.LastTransitionTime.Equal(&before) {
+		t.Errorf("expected lastTransitionTime %v to be preserved, got %v", before, cond.LastTransitionTime)
+	}
+	if cond.Reason != "StillPending" || cond.ObservedGeneration != 2 {
+		t.Errorf("expected reason and observedGeneration to be updated, got %+v", cond)
+	}
+
+	// Status change: transition time is bumped.
+	if _, err := Set(w, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Done"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	cond = Get(w, "Ready")
+	if !cond.LastTransitionTime.After(before.Time) {
+		t.Errorf("expected lastTransitionTime to advance past %v, got %v", before, cond.LastTransitionTime)
+	}
+	if !IsTrue(w, "Ready") {
+		t.Error("expected Ready to be True")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	w := &widget{}
+	removed, err := Remove(w, "Ready")
+	if err != nil || removed {
+		t.Errorf("expected nothing to remove from nil status, got %v, %v", removed, err)
+	}
+
+	if _, err := Set(w, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Done"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	removed, err = Remove(w, "Ready")
+	if err != nil || !removed {
+		t.Errorf("expected Ready to be removed, got %v, %v", removed, err)
+	}
+	if Get(w, "Ready") != nil {
+		t.Error("expected Ready condition to be gone")
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	cm := &corev1.ConfigMap{}
+	if _, err := Set(cm, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue}); err == nil {
+		t.Error("expected error setting condition on ConfigMap")
+	}
+	if Get(cm, "Ready") != nil || IsTrue(cm, "Ready") {
+		t.Error("expected no conditions on ConfigMap")
+	}
+}
+
+func TestNil(t *testing.T) {
+	var pdb *policyv1.PodDisruptionBudget
+	var w *widget
+	var obj runtime.Object
+	for _, tt := range []struct {
+		name string
+		set  func() error
+		list func() []metav1.Condition
+	}{{
+		name: "nil pointer",
+		set: func() error {
+			_, err := Set(pdb, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue})
+			return err
+		},
+		list: func() []metav1.Condition { return List(pdb) },
+	}, {
+		name: "nil pointer with pointer status",
+		set: func() error {
+			_, err := Set(w, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue})
+			return err
+		},
+		list: func() []metav1.Condition { return List(w) },
+	}, {
+		name: "nil interface",
+		set: func() error {
+			_, err := Set(obj, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue})
+			return err
+		},
+		list: func() []metav1.Condition { return List(obj) },
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.set(); err == nil {
+				t.Error("expected error setting a condition on a nil object")
+			}
+			if conds := tt.list(); conds != nil {
+				t.Errorf("expected no conditions on a nil object, got %v", conds)
+			}
+		})
+	}
+	if _, err := Remove(obj, "Ready"); err == nil {
+		t.Error("expected error removing a condition from a nil object")
+	}
+	if Get(obj, "Ready") != nil || IsTrue(obj, "Ready") || IsFalse(obj, "Ready") {
+		t.Error("expected no conditions on a nil object")
+	}
+}
+
+// pdbTransport serves a single PodDisruptionBudget list and watch.
+type pdbTransport struct {
+	list  string
+	watch string
+}
+
+func (p *pdbTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := p.list
+	if req.URL.Query().Get("watch") == "true" {
+		body = p.watch
+	}
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+	}, nil
+}
+
+func TestWaitForCondition(t *testing.T) {
+	// The listed object has a Ready=True condition for a previous
+	// generation, which must not satisfy the wait.
+	client := generic.NewClientGVR[*policyv1.PodDisruptionBudget](
+		schema.GroupVersionResource{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"},
+		&rest.Config{Host: "http://test", Transport: &pdbTransport{
+			list: `{"kind": "PodDisruptionBudgetList", "apiVersion": "policy/v1", "metadata": {"resourceVersion": "1"}, "items": [
+				{"kind": "PodDisruptionBudget", "apiVersion": "policy/v1", "metadata": {"name": "pdb", "namespace": "default", "generation": 2, "resourceVersion": "1"},
+				 "status": {"conditions": [{"type": "Ready", "status": "True", "reason": "Old", "observedGeneration": 1, "lastTransitionTime": "2024-01-01T00:00:00Z"}]}}
+			]}`,
+			watch: `{"type": "MODIFIED", "object": {"kind": "PodDisruptionBudget", "apiVersion": "policy/v1", "metadata": {"name": "pdb", "namespace": "default", "generation": 2, "resourceVersion": "2"},
+				 "status": {"conditions": [{"type": "Ready", "status": "True", "reason": "New", "observedGeneration": 2, "lastTransitionTime": "2024-01-01T00:00:00Z"}]}}}` + "\n",
+		}},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pdb, err := WaitForCondition(ctx, client, "default", "pdb", "Ready", metav1.ConditionTrue)
+	if err != nil {
+		t.Fatalf("WaitForCondition failed: %v", err)
+	}
+	if got := Get(pdb, "Ready").Reason; got != "New" {
+		t.Errorf("expected condition from current generation, got reason %q", got)
+	}
+}
@@ -0,0 +1,36 @@
+package conditions
+
+import (
+	"context"
+
+	"github.com/imjasonh/client-go2/generic"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// WaitForCondition blocks until the named object has a condition of the given
+// type with the given status, or ctx is done.
+//
+// Conditions that report an ObservedGeneration older than the object's
+// metadata.generation are considered stale and are not matched.
+//
+// Like generic.Client.WaitFor, it returns the last observed version of the
+// object, including on timeout.
+func WaitForCondition[T runtime.Object](ctx context.Context, c generic.Client[T], namespace, name, conditionType string, status metav1.ConditionStatus) (T, error) {
+	return c.WaitFor(ctx, namespace, name, func(obj T) (bool, error) {
+		return hasCondition(obj, conditionType, status), nil
+	})
+}
+
+// hasCondition returns true if obj has an up-to-date condition of the given type and status.
+func hasCondition[T runtime.Object](obj T, conditionType string, status metav1.ConditionStatus) bool {
+	cond := Get(obj, conditionType)
+	if cond == nil || cond.Status != status {
+		return false
+	}
+	if m, err := meta.Accessor(obj); err == nil && cond.ObservedGeneration != 0 && cond.ObservedGeneration < m.GetGeneration() {
+		return false
+	}
+	return true
+}
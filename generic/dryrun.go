@@ -0,0 +1,41 @@
+package generic
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// DryRun returns a copy of the client that sends every mutating request
+// (create, update, patch, delete and deletecollection, including status
+// updates and its PodClient's bindings and evictions) with dryRun=All. The
+// API server validates and admits the request and returns the object as it
+// would have been persisted, but does not persist it.
+//
+// Reads are unaffected, so a dry-run client sees the real state of the
+// cluster rather than the results of its own writes.
+func (c Client[T]) DryRun() Client[T] {
+	c.dryRun = true
+	return c
+}
+
+// IsDryRun returns true if the client sends mutating requests with dryRun=All.
+func (c Client[T]) IsDryRun() bool {
+	return c.dryRun
+}
+
+// mutatingVerbs are the verbs affected by DryRun.
+var mutatingVerbs = map[string]bool{
+	"create":           true,
+	"update":           true,
+	"patch":            true,
+	"delete":           true,
+	"deletecollection": true,
+}
+
+// applyDryRun adds dryRun=All to req if the client is in dry-run mode and
+// the request is mutating and doesn't already set it.
+func (c Client[T]) applyDryRun(info RequestInfo, req *rest.Request) {
+	if c.dryRun && mutatingVerbs[info.Verb] && !req.URL().Query().Has("dryRun") {
+		req.Param("dryRun", metav1.DryRunAll)
+	}
+}
@@ -0,0 +1,80 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	pod := `{"kind":"Pod","apiVersion":"v1","metadata":{"name":"p","namespace":"default"}}`
+	client := NewClientGVR[*corev1.Pod](
+		schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+		&rest.Config{
+			Host: "http://localhost",
+			Transport: &mockTransport{
+				// Mutating requests only succeed with dryRun=All, and reads only without it.
+				responses: map[string]mockResponse{
+					"GET /api/v1/namespaces/default/pods/p":                   {statusCode: 200, body: pod},
+					"POST /api/v1/namespaces/default/pods?dryRun=All":         {statusCode: 201, body: pod},
+					"PUT /api/v1/namespaces/default/pods/p?dryRun=All":        {statusCode: 200, body: pod},
+					"PUT /api/v1/namespaces/default/pods/p/status?dryRun=All": {statusCode: 200, body: pod},
+					"PATCH /api/v1/namespaces/default/pods/p?dryRun=All":      {statusCode: 200, body: pod},
+					"DELETE /api/v1/namespaces/default/pods/p?dryRun=All":     {statusCode: 200, body: `{}`},
+					"DELETE /api/v1/namespaces/default/pods?dryRun=All":       {statusCode: 200, body: `{}`},
+					"POST /api/v1/namespaces/default/pods":                    {statusCode: 500, body: `{}`},
+					"PUT /api/v1/namespaces/default/pods/p":                   {statusCode: 500, body: `{}`},
+					"PUT /api/v1/namespaces/default/pods/p/status":            {statusCode: 500, body: `{}`},
+					"PATCH /api/v1/namespaces/default/pods/p":                 {statusCode: 500, body: `{}`},
+					"DELETE /api/v1/namespaces/default/pods/p":                {statusCode: 500, body: `{}`},
+					"DELETE /api/v1/namespaces/default/pods":                  {statusCode: 500, body: `{}`},
+				},
+			},
+		},
+	)
+	if client.IsDryRun() {
+		t.Fatal("expected new client not to be in dry-run mode")
+	}
+	dryRun := client.DryRun()
+	if !dryRun.IsDryRun() {
+		t.Fatal("expected DryRun client to be in dry-run mode")
+	}
+
+	p, err := dryRun.Get(ctx, "default", "p", nil)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if _, err := dryRun.Create(ctx, "default", p, nil); err != nil {
+		t.Errorf("Create failed: %v", err)
+	}
+	if _, err := dryRun.Update(ctx, "default", p, nil); err != nil {
+		t.Errorf("Update failed: %v", err)
+	}
+	if _, err := dryRun.UpdateStatus(ctx, "default", p, nil); err != nil {
+		t.Errorf("UpdateStatus failed: %v", err)
+	}
+	if err := dryRun.Patch(ctx, "default", "p", types.MergePatchType, []byte(`{}`), nil); err != nil {
+		t.Errorf("Patch failed: %v", err)
+	}
+	if err := dryRun.Delete(ctx, "default", "p", nil); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if err := dryRun.DeleteCollection(ctx, "default", nil, nil); err != nil {
+		t.Errorf("DeleteCollection failed: %v", err)
+	}
+	// Options that already request a dry run are sent unchanged.
+	if _, err := dryRun.Create(ctx, "default", p, &metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}); err != nil {
+		t.Errorf("Create with DryRun option failed: %v", err)
+	}
+
+	// The original client is unaffected.
+	if _, err := client.Create(ctx, "default", p, nil); err == nil {
+		t.Error("expected Create without dry-run to fail")
+	}
+}
@@ -25,6 +25,8 @@ var _ typedcorev1.PodExpansion = PodClient{}
 
 // GetLogs returns a request for the logs of a pod.
 // This matches the signature from k8s.io/client-go/kubernetes/typed/core/v1
+//
+// As with Client.SubResource, the caller executes the request.
 func (p PodClient) GetLogs(name string, opts *corev1.PodLogOptions) *rest.Request {
 	req := p.client.SubResource(p.namespace, name, "log")
 	if opts != nil {
@@ -36,46 +38,45 @@ func (p PodClient) GetLogs(name string, opts *corev1.PodLogOptions) *rest.Reques
 // Bind binds a pod to a node.
 // This matches the signature from k8s.io/client-go/kubernetes/typed/core/v1
 func (p PodClient) Bind(ctx context.Context, binding *corev1.Binding, opts metav1.CreateOptions) error {
-	body, err := p.client.RESTClient().Post().
+	req := p.client.RESTClient().Post().
 		Namespace(p.namespace).
 		Resource("pods").
 		Name(binding.Name).
 		SubResource("binding").
 		VersionedParams(&opts, scheme.ParameterCodec).
-		Body(binding).
-		Do(ctx).
-		Raw()
-	if err != nil {
-		return err
-	}
+		Body(binding)
 	// binding returns empty response
-	_ = body
-	return nil
+	return p.create(ctx, binding.Name, "binding", req)
 }
 
 // Evict evicts a pod using policy/v1beta1 API.
 // This matches the signature from k8s.io/client-go/kubernetes/typed/core/v1
 func (p PodClient) Evict(ctx context.Context, eviction *policyv1beta1.Eviction) error {
-	return p.client.RESTClient().Post().
+	req := p.client.RESTClient().Post().
 		Namespace(p.namespace).
 		Resource("pods").
 		Name(eviction.Name).
 		SubResource("eviction").
-		Body(eviction).
-		Do(ctx).
-		Error()
+		Body(eviction)
+	return p.create(ctx, eviction.Name, "eviction", req)
 }
 
 // EvictV1 evicts a pod using policy/v1 API.
 func (p PodClient) EvictV1(ctx context.Context, eviction *policyv1.Eviction) error {
-	return p.client.RESTClient().Post().
+	req := p.client.RESTClient().Post().
 		Namespace(p.namespace).
 		Resource("pods").
 		Name(eviction.Name).
 		SubResource("eviction").
-		Body(eviction).
-		Do(ctx).
-		Error()
+		Body(eviction)
+	return p.create(ctx, eviction.Name, "eviction", req)
+}
+
+// create sends req, a POST to a subresource of the named pod, like the
+// client's own requests: instrumented, and with dryRun=All if the client is
+// in dry-run mode.
+func (p PodClient) create(ctx context.Context, name, subresource string, req *rest.Request) error {
+	return p.client.do(ctx, RequestInfo{Verb: "create", Subresource: subresource, Namespace: p.namespace, Name: name}, req).Error()
 }
 
 // EvictV1beta1 evicts a pod using policy/v1beta1 API.
@@ -84,6 +85,8 @@ func (p PodClient) EvictV1beta1(ctx context.Context, eviction *policyv1beta1.Evi
 }
 
 // ProxyGet returns a proxy connection to the pod.
+//
+// As with Client.SubResource, the caller executes the request.
 func (p PodClient) ProxyGet(scheme, name, port, path string, params map[string]string) rest.ResponseWrapper {
 	request := p.client.RESTClient().Get().
 		Namespace(p.namespace).
@@ -108,6 +111,8 @@ type ServiceClient struct {
 var _ typedcorev1.ServiceExpansion = ServiceClient{}
 
 // ProxyGet returns a proxy connection to the service.
+//
+// As with Client.SubResource, the caller executes the request.
 func (s ServiceClient) ProxyGet(scheme, name, port, path string, params map[string]string) rest.ResponseWrapper {
 	request := s.client.RESTClient().Get().
 		Namespace(s.namespace).
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -381,3 +382,31 @@ func TestSubResource(t *testing.T) {
 		t.Errorf("expected status to contain 'Running', got %q", string(body))
 	}
 }
+
+func TestPodClientDryRun(t *testing.T) {
+	mt := &mockTransport{
+		// Requests only succeed with dryRun=All.
+		responses: map[string]mockResponse{
+			"POST /api/v1/namespaces/default/pods/test-pod/binding?dryRun=All":  {statusCode: http.StatusCreated},
+			"POST /api/v1/namespaces/default/pods/test-pod/eviction?dryRun=All": {statusCode: http.StatusCreated},
+			"POST /api/v1/namespaces/default/pods/test-pod/binding":             {statusCode: http.StatusInternalServerError},
+			"POST /api/v1/namespaces/default/pods/test-pod/eviction":            {statusCode: http.StatusInternalServerError},
+		},
+	}
+	client := NewClientGVR[*corev1.Pod](
+		schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+		&rest.Config{Host: "http://localhost:8080", Transport: mt},
+	).DryRun().PodClient("default")
+
+	ctx := context.Background()
+	meta := metav1.ObjectMeta{Name: "test-pod", Namespace: "default"}
+	if err := client.Bind(ctx, &corev1.Binding{ObjectMeta: meta}, metav1.CreateOptions{}); err != nil {
+		t.Errorf("Bind failed: %v", err)
+	}
+	if err := client.Evict(ctx, &policyv1beta1.Eviction{ObjectMeta: meta}); err != nil {
+		t.Errorf("Evict failed: %v", err)
+	}
+	if err := client.EvictV1(ctx, &policyv1.Eviction{ObjectMeta: meta}); err != nil {
+		t.Errorf("EvictV1 failed: %v", err)
+	}
+}
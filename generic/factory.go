@@ -0,0 +1,145 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// InformerFactory hands out shared informers, so that every consumer of the
+// same resource, namespace and selectors shares a single watch and cache.
+//
+//	factory := generic.NewInformerFactory()
+//	pods := podClient.SharedInformer(factory, nil)
+//	pods.AddHandler(handler)
+//	factory.Start(ctx)
+//	if err := factory.WaitForCacheSync(ctx); err != nil {
+//	    return err
+//	}
+//	lister := pods.Lister()
+//
+// Informers are not started until Start is called.
+type InformerFactory struct {
+	mu        sync.Mutex
+	informers map[informerKey]cache.SharedIndexInformer
+	started   map[informerKey]bool
+}
+
+// informerKey identifies the informers that can be shared.
+type informerKey struct {
+	gvr           schema.GroupVersionResource
+	namespace     string
+	labelSelector string
+	fieldSelector string
+	metadataOnly  bool
+}
+
+func (k informerKey) String() string {
+	s := k.gvr.String()
+	if k.metadataOnly {
+		s += " (metadata)"
+	}
+	if k.namespace != "" {
+		s += " in " + k.namespace
+	}
+	if k.labelSelector != "" {
+		s += " labels=" + k.labelSelector
+	}
+	if k.fieldSelector != "" {
+		s += " fields=" + k.fieldSelector
+	}
+	return s
+}
+
+// NewInformerFactory creates an empty InformerFactory.
+func NewInformerFactory() *InformerFactory {
+	return &InformerFactory{
+		informers: make(map[informerKey]cache.SharedIndexInformer),
+		started:   make(map[informerKey]bool),
+	}
+}
+
+// SharedInformer returns the informer from f for objects of type T matching
+// opts, creating it if this is the first request for them.
+//
+// The informer is keyed by resource, namespace, label and field selectors,
+// and whether the client is a MetadataClient. opts.ResyncPeriod and
+// opts.Transform only apply when the informer is created. opts.Indexers are added to an existing
+// informer, except those with the name of an index it already has, which
+// are assumed to be equivalent.
+//
+// A newly created informer is not started until f.Start is called.
+func (c Client[T]) SharedInformer(f *InformerFactory, opts *InformOptions[T]) *Informer[T] {
+	if opts == nil {
+		opts = &InformOptions[T]{}
+	}
+	key := informerKey{
+		gvr:           c.gvr,
+		namespace:     opts.Namespace,
+		labelSelector: opts.ListOptions.LabelSelector,
+		fieldSelector: opts.ListOptions.FieldSelector,
+		metadataOnly:  c.metadataOnly,
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	informer, ok := f.informers[key]
+	if !ok {
+		informer = c.newInformer(opts)
+		f.informers[key] = informer
+	} else if len(opts.Indexers) > 0 {
+		existing := informer.GetIndexer().GetIndexers()
+		indexers := cache.Indexers{}
+		for name, index := range toIndexers(opts.Indexers) {
+			if _, ok := existing[name]; !ok {
+				indexers[name] = index
+			}
+		}
+		// This can only fail if the informer has stopped, in which case its
+		// indexes will never be used.
+		_ = informer.AddIndexers(indexers)
+	}
+	return newInformerHandle(c, informer, opts, nil)
+}
+
+// Start starts every informer that has been requested from f and not yet
+// started. It can be called again to start informers requested since.
+// The informers run until ctx is done.
+func (f *InformerFactory) Start(ctx context.Context) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key, informer := range f.informers {
+		if !f.started[key] {
+			go informer.RunWithContext(ctx)
+			f.started[key] = true
+		}
+	}
+}
+
+// WaitForCacheSync blocks until every started informer has synced, or
+// returns an error listing those that have not if ctx is done first.
+func (f *InformerFactory) WaitForCacheSync(ctx context.Context) error {
+	f.mu.Lock()
+	informers := make(map[informerKey]cache.SharedIndexInformer, len(f.started))
+	for key := range f.started {
+		informers[key] = f.informers[key]
+	}
+	f.mu.Unlock()
+
+	var unsynced []string
+	for key, informer := range informers {
+		if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+			unsynced = append(unsynced, key.String())
+		}
+	}
+	if len(unsynced) > 0 {
+		sort.Strings(unsynced)
+		return fmt.Errorf("failed to sync informers for %s", strings.Join(unsynced, ", "))
+	}
+	return nil
+}
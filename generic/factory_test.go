@@ -0,0 +1,126 @@
+package generic
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// forbidden is the response of an API server to a request it forbids.
+var forbidden = mockResponse{
+	statusCode: http.StatusForbidden,
+	body:       `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`,
+}
+
+func TestInformerFactory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	transport := listWatch(pendingPodList, "")
+	client := newWaitTestClient(transport)
+	factory := NewInformerFactory()
+
+	first := client.SharedInformer(factory, nil)
+	second := client.SharedInformer(factory, &InformOptions[*corev1.Pod]{})
+	if first.Informer() != second.Informer() {
+		t.Error("expected informers with the same options to be shared")
+	}
+	if other := client.SharedInformer(factory, &InformOptions[*corev1.Pod]{Namespace: "other"}); other.Informer() == first.Informer() {
+		t.Error("expected informers for different namespaces not to be shared")
+	}
+	if other := client.SharedInformer(factory, &InformOptions[*corev1.Pod]{ListOptions: metav1.ListOptions{LabelSelector: "app=web"}}); other.Informer() == first.Informer() {
+		t.Error("expected informers with different selectors not to be shared")
+	}
+
+	var mu sync.Mutex
+	added := map[string][]string{}
+	handler := func(name string) InformerHandler[*corev1.Pod] {
+		return InformerHandler[*corev1.Pod]{OnAdd: func(key string, _ *corev1.Pod) {
+			mu.Lock()
+			defer mu.Unlock()
+			added[name] = append(added[name], key)
+		}}
+	}
+	reg1, err := first.AddHandler(handler("first"))
+	if err != nil {
+		t.Fatalf("AddHandler failed: %v", err)
+	}
+	reg2, err := second.AddHandler(handler("second"))
+	if err != nil {
+		t.Fatalf("AddHandler failed: %v", err)
+	}
+
+	if first.HasSynced() {
+		t.Error("expected informer not to have synced before Start")
+	}
+	factory.Start(ctx)
+	factory.Start(ctx) // Starting again is a no-op for running informers.
+	if err := factory.WaitForCacheSync(ctx); err != nil {
+		t.Fatalf("WaitForCacheSync failed: %v", err)
+	}
+	if !eventually(ctx, reg1.HasSynced) || !eventually(ctx, reg2.HasSynced) {
+		t.Fatal("handlers did not sync")
+	}
+
+	mu.Lock()
+	if len(added["first"]) != 1 || len(added["second"]) != 1 || added["first"][0] != "default/p" {
+		t.Errorf("expected both handlers to see default/p, got %v", added)
+	}
+	mu.Unlock()
+
+	lists := 0
+	for _, req := range transport.requests() {
+		if req.URL.Path == "/api/v1/pods" && req.URL.Query().Get("watch") != "true" && !req.URL.Query().Has("labelSelector") {
+			lists++
+		}
+	}
+	if lists != 1 {
+		t.Errorf("expected a single list of all pods, got %d", lists)
+	}
+
+	if _, err := second.Lister().ByNamespace("default").Get("p"); err != nil {
+		t.Errorf("expected p in shared cache: %v", err)
+	}
+	if err := first.RemoveHandler(reg1); err != nil {
+		t.Errorf("RemoveHandler failed: %v", err)
+	}
+}
+
+func TestInformerFactorySyncFailure(t *testing.T) {
+	client := NewClientGVR[*corev1.Pod](
+		schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+		&rest.Config{Host: "http://test", Transport: &mockTransport{responses: map[string]mockResponse{"GET": forbidden}}},
+	)
+	factory := NewInformerFactory()
+	client.SharedInformer(factory, &InformOptions[*corev1.Pod]{Namespace: "default"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	factory.Start(ctx)
+	err := factory.WaitForCacheSync(ctx)
+	if err == nil {
+		t.Fatal("expected WaitForCacheSync to fail")
+	}
+	if !strings.Contains(err.Error(), "pods in default") {
+		t.Errorf("expected error to name the unsynced informer, got %v", err)
+	}
+}
+
+// eventually polls cond until it returns true or ctx is done.
+func eventually(ctx context.Context, cond func() bool) bool {
+	for !cond() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package generic
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FieldSelector returns a predicate that matches objects against a
+// field selector, for use with Lister.Filter:
+//
+//	onNode, err := generic.FieldSelector[*corev1.Pod]("spec.nodeName=node-1,status.phase!=Succeeded")
+//	if err != nil {
+//	    return err
+//	}
+//	for pod := range lister.Filter(onNode) {
+//	    // ...
+//	}
+//
+// Unlike server-side field selectors, any field path may be used. Paths are
+// JSON field names separated by dots; the final field must be a string,
+// bool or number, or the selector never matches. Map values can be selected
+// by key, e.g. "metadata.labels.app".
+func FieldSelector[T runtime.Object](selector string) (func(T) bool, error) {
+	sel, err := fields.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	reqs := sel.Requirements()
+	return func(obj T) bool {
+		set := make(fields.Set, len(reqs))
+		v := reflect.ValueOf(obj)
+		for _, r := range reqs {
+			if val, ok := fieldValue(v, r.Field); ok {
+				set[r.Field] = val
+			}
+		}
+		return sel.Matches(set)
+	}, nil
+}
+
+// fieldValue returns the string form of the value at the dot-separated JSON
+// path within v.
+func fieldValue(v reflect.Value, path string) (string, bool) {
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return "", false
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			index, ok := jsonFieldIndex(v.Type(), name)
+			if !ok {
+				return "", false
+			}
+			var err error
+			if v, err = v.FieldByIndexErr(index); err != nil {
+				return "", false
+			}
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return "", false
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return "", false
+			}
+		default:
+			return "", false
+		}
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// jsonFields caches the field index of each JSON field name, per struct type.
+var jsonFields sync.Map // map[reflect.Type]map[string][]int
+
+// jsonFieldIndex returns the index of the field of typ with the given JSON
+// name, including fields promoted from inlined structs.
+func jsonFieldIndex(typ reflect.Type, name string) ([]int, bool) {
+	if m, ok := jsonFields.Load(typ); ok {
+		index, ok := m.(map[string][]int)[name]
+		return index, ok
+	}
+	m := map[string][]int{}
+	collectJSONFields(typ, nil, m)
+	jsonFields.Store(typ, m)
+	index, ok := m[name]
+	return index, ok
+}
+
+// collectJSONFields adds the JSON field names of typ to m, recursing into
+// embedded and inline structs. Shallower fields take precedence.
+func collectJSONFields(typ reflect.Type, prefix []int, m map[string][]int) {
+	var inline []reflect.StructField
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if (f.Anonymous && name == "") || strings.Contains(","+opts+",", ",inline,") {
+			inline = append(inline, f)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := m[name]; !ok {
+			m[name] = append(append([]int(nil), prefix...), i)
+		}
+	}
+	for _, f := range inline {
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct {
+			continue
+		}
+		collectJSONFields(ft, append(append([]int(nil), prefix...), f.Index...), m)
+	}
+}
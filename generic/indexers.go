@@ -0,0 +1,82 @@
+package generic
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// OwnerUIDIndex is the conventional name of the IndexByOwnerUID index.
+	OwnerUIDIndex = "ownerUID"
+	// NodeNameIndex is the conventional name of the IndexPodByNodeName index.
+	NodeNameIndex = "nodeName"
+)
+
+// LabelIndex returns the conventional name of the IndexByLabel index for
+// the label key.
+func LabelIndex(key string) string {
+	return "label:" + key
+}
+
+// IndexByOwnerUID indexes objects by the UIDs of their owners, so that
+// Lister.ByIndex(OwnerUIDIndex, string(owner.UID)) returns the objects
+// owned by owner.
+func IndexByOwnerUID[T runtime.Object](obj T) ([]string, error) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	refs := m.GetOwnerReferences()
+	uids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		uids = append(uids, string(ref.UID))
+	}
+	return uids, nil
+}
+
+// IndexPodByNodeName indexes Pods by the node they are scheduled to, so that
+// Lister.ByIndex(NodeNameIndex, node) returns the Pods on node. Unscheduled
+// Pods are not indexed.
+func IndexPodByNodeName(pod *corev1.Pod) ([]string, error) {
+	if pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+	return []string{pod.Spec.NodeName}, nil
+}
+
+// IndexByLabel returns an index function that indexes objects by the value
+// of the label key, so that Lister.ByIndex(LabelIndex(key), value) returns
+// the objects with that label value. Objects without the label are not
+// indexed.
+func IndexByLabel[T runtime.Object](key string) func(T) ([]string, error) {
+	return func(obj T) ([]string, error) {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		if v, ok := m.GetLabels()[key]; ok {
+			return []string{v}, nil
+		}
+		return nil, nil
+	}
+}
+
+// toIndexers converts typed index functions to cache.Indexers.
+func toIndexers[T runtime.Object](typed map[string]func(T) ([]string, error)) cache.Indexers {
+	indexers := make(cache.Indexers, len(typed)+1)
+	for name, index := range typed {
+		indexers[name] = func(obj any) ([]string, error) {
+			t, ok := obj.(T)
+			if !ok {
+				var zero T
+				return nil, fmt.Errorf("expected type %T, got %T", zero, obj)
+			}
+			return index(t)
+		}
+	}
+	return indexers
+}
@@ -0,0 +1,104 @@
+package generic
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+func indexTestPod(name, node string, labels map[string]string, owners ...types.UID) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: node},
+	}
+	for _, uid := range owners {
+		pod.OwnerReferences = append(pod.OwnerReferences, metav1.OwnerReference{UID: uid})
+	}
+	return pod
+}
+
+func names(pods []*corev1.Pod) []string {
+	var out []string
+	for _, pod := range pods {
+		out = append(out, pod.Name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestListerByIndex(t *testing.T) {
+	client := NewClientGVR[*corev1.Pod](schema.GroupVersionResource{Version: "v1", Resource: "pods"}, &rest.Config{Host: "http://test"})
+	informer := client.newInformer(&InformOptions[*corev1.Pod]{
+		Indexers: map[string]func(*corev1.Pod) ([]string, error){
+			OwnerUIDIndex:     IndexByOwnerUID[*corev1.Pod],
+			NodeNameIndex:     IndexPodByNodeName,
+			LabelIndex("app"): IndexByLabel[*corev1.Pod]("app"),
+		},
+	})
+	for _, pod := range []*corev1.Pod{
+		indexTestPod("a", "node-1", map[string]string{"app": "web"}, "rs-1"),
+		indexTestPod("b", "node-1", map[string]string{"app": "web"}, "rs-1", "rs-2"),
+		indexTestPod("c", "node-2", map[string]string{"app": "db"}, "rs-2"),
+		indexTestPod("d", "", nil),
+	} {
+		if err := informer.GetIndexer().Add(pod); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	lister := NewLister[*corev1.Pod](informer, client.gvr.GroupResource())
+
+	for _, tt := range []struct {
+		index, value string
+		want         []string
+	}{
+		{OwnerUIDIndex, "rs-1", []string{"a", "b"}},
+		{OwnerUIDIndex, "rs-2", []string{"b", "c"}},
+		{OwnerUIDIndex, "rs-3", nil},
+		{NodeNameIndex, "node-1", []string{"a", "b"}},
+		{NodeNameIndex, "", nil},
+		{LabelIndex("app"), "web", []string{"a", "b"}},
+		{LabelIndex("app"), "db", []string{"c"}},
+	} {
+		pods, err := lister.ByIndex(tt.index, tt.value)
+		if err != nil {
+			t.Fatalf("ByIndex(%q, %q) failed: %v", tt.index, tt.value, err)
+		}
+		if got := names(pods); !slices.Equal(got, tt.want) {
+			t.Errorf("ByIndex(%q, %q) = %v, want %v", tt.index, tt.value, got, tt.want)
+		}
+	}
+
+	keys, err := lister.IndexKeys(OwnerUIDIndex, "rs-2")
+	if err != nil {
+		t.Fatalf("IndexKeys failed: %v", err)
+	}
+	sort.Strings(keys)
+	if !slices.Equal(keys, []string{"default/b", "default/c"}) {
+		t.Errorf("IndexKeys = %v", keys)
+	}
+
+	if _, err := lister.ByIndex("missing", "x"); err == nil {
+		t.Error("expected error for an index that does not exist")
+	}
+}
+
+func TestSharedInformerAddsIndexers(t *testing.T) {
+	client := NewClientGVR[*corev1.Pod](schema.GroupVersionResource{Version: "v1", Resource: "pods"}, &rest.Config{Host: "http://test"})
+	factory := NewInformerFactory()
+	first := client.SharedInformer(factory, nil)
+	second := client.SharedInformer(factory, &InformOptions[*corev1.Pod]{
+		Indexers: map[string]func(*corev1.Pod) ([]string, error){NodeNameIndex: IndexPodByNodeName},
+	})
+	if first.Informer() != second.Informer() {
+		t.Fatal("expected informers to be shared")
+	}
+	if _, ok := first.Informer().GetIndexer().GetIndexers()[NodeNameIndex]; !ok {
+		t.Error("expected index to be added to the shared informer")
+	}
+}
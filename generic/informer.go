@@ -0,0 +1,104 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Informer is a typed handle on a running informer for objects of type T,
+// returned by Inform and SharedInformer.
+//
+// Any number of handlers can be attached to and detached from the same
+// informer, and all of them share its watch and cache.
+type Informer[T runtime.Object] struct {
+	informer cache.SharedIndexInformer
+	gvr      schema.GroupVersionResource
+	// namespace is the namespace the informer is limited to, if any.
+	namespace string
+	// selective is true if the informer only caches objects matching a
+	// label or field selector.
+	selective bool
+	// stop stops the informer, if it is not shared.
+	stop context.CancelFunc
+}
+
+// newInformerHandle returns a handle on informer, which was created by c
+// with opts.
+func newInformerHandle[T runtime.Object](c Client[T], informer cache.SharedIndexInformer, opts *InformOptions[T], stop context.CancelFunc) *Informer[T] {
+	i := &Informer[T]{informer: informer, gvr: c.gvr, stop: stop}
+	if opts != nil {
+		i.namespace = opts.Namespace
+		i.selective = opts.ListOptions.LabelSelector != "" || opts.ListOptions.FieldSelector != ""
+	}
+	return i
+}
+
+// Lister returns a Lister for the informer's cache.
+func (i *Informer[T]) Lister() *Lister[T] {
+	return NewLister[T](i.informer, i.gvr.GroupResource())
+}
+
+// HasSynced returns true once the informer's cache has been populated by
+// its initial list.
+func (i *Informer[T]) HasSynced() bool {
+	return i.informer.HasSynced()
+}
+
+// WaitForSync blocks until the informer has synced, or returns an error if
+// ctx is done or the informer is stopped first.
+func (i *Informer[T]) WaitForSync(ctx context.Context) error {
+	if !cache.WaitForNamedCacheSync(i.gvr.String(), ctx.Done(), func() bool {
+		return i.informer.HasSynced() || i.informer.IsStopped()
+	}) {
+		return fmt.Errorf("failed to sync informer for %s", i.gvr.String())
+	}
+	if !i.informer.HasSynced() {
+		return fmt.Errorf("informer for %s stopped before it synced", i.gvr.String())
+	}
+	return nil
+}
+
+// LastSyncResourceVersion returns the resource version the informer last
+// observed when listing or watching, or "" if it has not synced.
+func (i *Informer[T]) LastSyncResourceVersion() string {
+	return i.informer.LastSyncResourceVersion()
+}
+
+// AddHandler attaches handler to the informer. If the informer has already
+// synced, handler is first called with OnAdd for every object in the cache.
+//
+// The returned registration can be passed to RemoveHandler, and its
+// HasSynced reports when handler has been called for the initial objects.
+func (i *Informer[T]) AddHandler(handler InformerHandler[T]) (cache.ResourceEventHandlerRegistration, error) {
+	return i.informer.AddEventHandler(handler.resourceEventHandler())
+}
+
+// RemoveHandler detaches a handler previously attached with AddHandler.
+func (i *Informer[T]) RemoveHandler(registration cache.ResourceEventHandlerRegistration) error {
+	return i.informer.RemoveEventHandler(registration)
+}
+
+// Stop stops an informer started by Inform, closing its watch. Its cache
+// is left as it was. Stop is idempotent.
+//
+// Stop has no effect on informers from an InformerFactory, which are shared
+// and run until the context passed to the factory's Start is done.
+func (i *Informer[T]) Stop() {
+	if i.stop != nil {
+		i.stop()
+	}
+}
+
+// IsStopped returns true if the informer has stopped.
+func (i *Informer[T]) IsStopped() bool {
+	return i.informer.IsStopped()
+}
+
+// Informer returns the underlying SharedIndexInformer.
+func (i *Informer[T]) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
@@ -0,0 +1,202 @@
+package generic
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestHandlerDeleteTombstone(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "default"}}
+	tombstone := cache.DeletedFinalStateUnknown{Key: "default/p", Obj: pod}
+
+	t.Run("OnDelete", func(t *testing.T) {
+		var got []string
+		h := InformerHandler[*corev1.Pod]{
+			OnDelete: func(key string, obj *corev1.Pod) { got = append(got, key+" "+obj.Name) },
+			OnError:  func(obj any, err error) { t.Errorf("unexpected error: %v", err) },
+		}
+		h.resourceEventHandler().OnDelete(pod)
+		h.resourceEventHandler().OnDelete(tombstone)
+		if len(got) != 2 || got[0] != "default/p p" || got[1] != "default/p p" {
+			t.Errorf("expected OnDelete for both deletes, got %v", got)
+		}
+	})
+
+	t.Run("OnDeleteUnknown", func(t *testing.T) {
+		var deleted, unknown []string
+		h := InformerHandler[*corev1.Pod]{
+			OnDelete:        func(key string, obj *corev1.Pod) { deleted = append(deleted, key) },
+			OnDeleteUnknown: func(key string, obj *corev1.Pod) { unknown = append(unknown, key) },
+			OnError:         func(obj any, err error) { t.Errorf("unexpected error: %v", err) },
+		}
+		h.resourceEventHandler().OnDelete(pod)
+		h.resourceEventHandler().OnDelete(tombstone)
+		if len(deleted) != 1 || len(unknown) != 1 || unknown[0] != "default/p" {
+			t.Errorf("expected one OnDelete and one OnDeleteUnknown, got %v and %v", deleted, unknown)
+		}
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		var errs int
+		h := InformerHandler[*corev1.Pod]{
+			OnDelete: func(key string, obj *corev1.Pod) { t.Errorf("unexpected OnDelete for %s", key) },
+			OnError:  func(obj any, err error) { errs++ },
+		}
+		h.resourceEventHandler().OnDelete(cache.DeletedFinalStateUnknown{
+			Key: "default/cm",
+			Obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}},
+		})
+		if errs != 1 {
+			t.Errorf("expected one error, got %d", errs)
+		}
+	})
+}
+
+// TestHandlerDeleteMissedDuringWatchGap simulates a pod being deleted while
+// the informer's watch was broken. The informer only finds out when it
+// relists, and delivers the last known state of the pod.
+func TestHandlerDeleteMissedDuringWatchGap(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	podA := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default", ResourceVersion: "1"}}
+	podB := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default", ResourceVersion: "1"}}
+	var mu sync.Mutex
+	lists, watches := 0, 0
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			lists++
+			if lists == 1 {
+				return &corev1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}, Items: []corev1.Pod{podA, podB}}, nil
+			}
+			// b was deleted while the watch was down.
+			return &corev1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: "3"}, Items: []corev1.Pod{podA}}, nil
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			watches++
+			if watches == 1 {
+				// The watch's resource version has been compacted, forcing a relist.
+				return nil, apierrors.NewResourceExpired("too old resource version")
+			}
+			return watch.NewFake(), nil
+		},
+	}
+	informer := cache.NewSharedIndexInformer(lw, &corev1.Pod{}, 0, cache.Indexers{})
+
+	deleted := make(chan string, 1)
+	unknown := make(chan *corev1.Pod, 1)
+	if _, err := informer.AddEventHandler(InformerHandler[*corev1.Pod]{
+		OnDelete:        func(key string, _ *corev1.Pod) { deleted <- key },
+		OnDeleteUnknown: func(_ string, pod *corev1.Pod) { unknown <- pod },
+		OnError:         func(obj any, err error) { t.Errorf("unexpected error: %v", err) },
+	}.resourceEventHandler()); err != nil {
+		t.Fatalf("AddEventHandler failed: %v", err)
+	}
+	go informer.RunWithContext(ctx)
+
+	select {
+	case pod := <-unknown:
+		if pod.Name != "b" || pod.ResourceVersion != "1" {
+			t.Errorf("expected last known state of b, got %s@%s", pod.Name, pod.ResourceVersion)
+		}
+	case key := <-deleted:
+		t.Errorf("expected OnDeleteUnknown, got OnDelete for %s", key)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for OnDeleteUnknown")
+	}
+}
+
+func TestInformHandle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := newWaitTestClient(listWatch(pendingPodList, ""))
+
+	informer, err := client.Inform(ctx, InformerHandler[*corev1.Pod]{}, &InformOptions[*corev1.Pod]{NoWaitForSync: true})
+	if err != nil {
+		t.Fatalf("Inform failed: %v", err)
+	}
+	if err := informer.WaitForSync(ctx); err != nil {
+		t.Fatalf("WaitForSync failed: %v", err)
+	}
+	if !informer.HasSynced() {
+		t.Error("expected HasSynced after WaitForSync")
+	}
+	if rv := informer.LastSyncResourceVersion(); rv != "1" {
+		t.Errorf("expected LastSyncResourceVersion 1, got %q", rv)
+	}
+	if _, err := informer.Lister().ByNamespace("default").Get("p"); err != nil {
+		t.Errorf("expected p in cache: %v", err)
+	}
+
+	// Handlers added after sync are called for the objects already cached.
+	added := make(chan string, 1)
+	registration, err := informer.AddHandler(InformerHandler[*corev1.Pod]{
+		OnAdd: func(key string, _ *corev1.Pod) { added <- key },
+	})
+	if err != nil {
+		t.Fatalf("AddHandler failed: %v", err)
+	}
+	select {
+	case key := <-added:
+		if key != "default/p" {
+			t.Errorf("expected OnAdd for default/p, got %s", key)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for OnAdd")
+	}
+	if err := informer.RemoveHandler(registration); err != nil {
+		t.Errorf("RemoveHandler failed: %v", err)
+	}
+
+	informer.Stop()
+	informer.Stop()
+	if !eventually(ctx, informer.IsStopped) {
+		t.Fatal("informer did not stop")
+	}
+	if _, err := informer.Lister().ByNamespace("default").Get("p"); err != nil {
+		t.Errorf("expected cache to be kept after Stop: %v", err)
+	}
+}
+
+func TestInformSyncFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	client := newWaitTestClient(&mockTransport{responses: map[string]mockResponse{"GET": forbidden}})
+	if _, err := client.Inform(ctx, InformerHandler[*corev1.Pod]{}, nil); err == nil {
+		t.Fatal("expected Inform to fail when the informer cannot sync")
+	}
+}
+
+func TestWaitForSyncStopped(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := newWaitTestClient(&mockTransport{responses: map[string]mockResponse{"GET": forbidden}})
+	informer, err := client.Inform(ctx, InformerHandler[*corev1.Pod]{}, &InformOptions[*corev1.Pod]{NoWaitForSync: true})
+	if err != nil {
+		t.Fatalf("Inform failed: %v", err)
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		informer.Stop()
+	}()
+	if err := informer.WaitForSync(ctx); err == nil || !strings.Contains(err.Error(), "stopped") {
+		t.Errorf("WaitForSync returned %v, want an error that the informer stopped", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("WaitForSync did not return until the test timed out")
+	}
+}
@@ -1,8 +1,12 @@
 package generic
 
 import (
+	"cmp"
 	"fmt"
+	"iter"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -12,17 +16,21 @@ import (
 // Lister provides type-safe wrapper around cache.GenericLister.
 type Lister[T runtime.Object] struct {
 	genericLister cache.GenericLister
+	indexer       cache.Indexer
 }
 
 // NamespaceLister provides type-safe wrapper around cache.GenericNamespaceLister.
 type NamespaceLister[T runtime.Object] struct {
 	genericNamespaceLister cache.GenericNamespaceLister
+	lister                 *Lister[T]
+	namespace              string
 }
 
 // NewLister creates a new type-safe lister from an informer.
 func NewLister[T runtime.Object](informer cache.SharedIndexInformer, resource schema.GroupResource) *Lister[T] {
 	return &Lister[T]{
 		genericLister: cache.NewGenericLister(informer.GetIndexer(), resource),
+		indexer:       informer.GetIndexer(),
 	}
 }
 
@@ -60,10 +68,64 @@ func (l *Lister[T]) Get(name string) (T, error) {
 	return typed, nil
 }
 
+// ByIndex returns the objects whose index function for the named index
+// produced value. The index must have been added with InformOptions.Indexers,
+// or be cache.NamespaceIndex.
+func (l *Lister[T]) ByIndex(indexName, value string) ([]T, error) {
+	objs, err := l.indexer.ByIndex(indexName, value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]T, 0, len(objs))
+	for _, obj := range objs {
+		if typed, ok := obj.(T); ok {
+			result = append(result, typed)
+		}
+	}
+	return result, nil
+}
+
+// IndexKeys returns the keys ("namespace/name", or "name" for cluster-scoped
+// objects) of the objects whose index function for the named index produced
+// value.
+func (l *Lister[T]) IndexKeys(indexName, value string) ([]string, error) {
+	return l.indexer.IndexKeys(indexName, value)
+}
+
+// All returns an iterator over every object in the cache, in no particular
+// order. Objects are read from the cache one at a time as the iteration
+// proceeds, so objects deleted during iteration may be skipped.
+func (l *Lister[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, key := range l.indexer.ListKeys() {
+			obj, exists, err := l.indexer.GetByKey(key)
+			if err != nil || !exists {
+				continue
+			}
+			if typed, ok := obj.(T); ok && !yield(typed) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator over the objects in the cache for which keep
+// returns true. See All.
+//
+//	for pod := range lister.Filter(func(p *corev1.Pod) bool { return p.Spec.NodeName == node }) {
+//	    // ...
+//	}
+func (l *Lister[T]) Filter(keep func(T) bool) iter.Seq[T] {
+	return filter(l.All(), keep)
+}
+
 // ByNamespace returns a namespace-scoped lister.
 func (l *Lister[T]) ByNamespace(namespace string) *NamespaceLister[T] {
 	return &NamespaceLister[T]{
 		genericNamespaceLister: l.genericLister.ByNamespace(namespace),
+		lister:                 l,
+		namespace:              namespace,
 	}
 }
 
@@ -83,6 +145,32 @@ func (nl *NamespaceLister[T]) List(selector labels.Selector) ([]T, error) {
 	return result, nil
 }
 
+// All returns an iterator over every object in the namespace, in no
+// particular order. With metav1.NamespaceAll, it iterates over every object
+// in the cache.
+func (nl *NamespaceLister[T]) All() iter.Seq[T] {
+	if nl.namespace == metav1.NamespaceAll {
+		return nl.lister.All()
+	}
+	return func(yield func(T) bool) {
+		objs, err := nl.lister.indexer.ByIndex(cache.NamespaceIndex, nl.namespace)
+		if err != nil {
+			return
+		}
+		for _, obj := range objs {
+			if typed, ok := obj.(T); ok && !yield(typed) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator over the objects in the namespace for which
+// keep returns true.
+func (nl *NamespaceLister[T]) Filter(keep func(T) bool) iter.Seq[T] {
+	return filter(nl.All(), keep)
+}
+
 // Get returns the object with the given name in the namespace.
 func (nl *NamespaceLister[T]) Get(name string) (T, error) {
 	var zero T
@@ -98,3 +186,42 @@ func (nl *NamespaceLister[T]) Get(name string) (T, error) {
 
 	return typed, nil
 }
+
+// filter returns an iterator over the elements of seq for which keep returns true.
+func filter[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for t := range seq {
+			if keep(t) && !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// ByName orders objects by namespace and then name. Use it to sort the
+// results of a Lister:
+//
+//	pods := slices.SortedFunc(lister.All(), generic.ByName[*corev1.Pod])
+func ByName[T runtime.Object](a, b T) int {
+	am, bm := mustAccessor(a), mustAccessor(b)
+	return cmp.Or(
+		cmp.Compare(am.GetNamespace(), bm.GetNamespace()),
+		cmp.Compare(am.GetName(), bm.GetName()),
+	)
+}
+
+// ByCreationTimestamp orders objects from oldest to newest, breaking ties
+// by namespace and name.
+func ByCreationTimestamp[T runtime.Object](a, b T) int {
+	at, bt := mustAccessor(a).GetCreationTimestamp(), mustAccessor(b).GetCreationTimestamp()
+	return cmp.Or(at.Time.Compare(bt.Time), ByName(a, b))
+}
+
+// mustAccessor returns the metadata of obj, or empty metadata if it has none.
+func mustAccessor(obj runtime.Object) metav1.Object {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return &metav1.ObjectMeta{}
+	}
+	return m
+}
@@ -2,10 +2,12 @@ package generic
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
@@ -111,10 +113,11 @@ func TestLister(t *testing.T) {
 	}
 
 	// Start informer and get lister
-	lister, err := client.Inform(ctx, handler, nil)
+	informer, err := client.Inform(ctx, handler, nil)
 	if err != nil {
 		t.Fatalf("failed to start informer: %v", err)
 	}
+	lister := informer.Lister()
 
 	// Give time for initial sync
 	time.Sleep(100 * time.Millisecond)
@@ -163,3 +166,111 @@ func TestLister(t *testing.T) {
 		t.Error("expected error getting non-existent configmap")
 	}
 }
+
+func TestListerIteration(t *testing.T) {
+	client := NewClientGVR[*corev1.Pod](schema.GroupVersionResource{Version: "v1", Resource: "pods"}, &rest.Config{Host: "http://test"})
+	informer := client.newInformer(&InformOptions[*corev1.Pod]{})
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, pod := range []*corev1.Pod{
+		indexTestPod("c", "node-1", map[string]string{"app": "web"}),
+		indexTestPod("a", "node-2", map[string]string{"app": "db"}),
+		indexTestPod("b", "node-1", nil),
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "other"}},
+	} {
+		pod.CreationTimestamp = metav1.NewTime(base.Add(time.Duration(i) * time.Minute))
+		if err := informer.GetIndexer().Add(pod); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	lister := NewLister[*corev1.Pod](informer, client.gvr.GroupResource())
+
+	key := func(p *corev1.Pod) string { return p.Namespace + "/" + p.Name }
+	keys := func(pods []*corev1.Pod) []string {
+		var out []string
+		for _, p := range pods {
+			out = append(out, key(p))
+		}
+		return out
+	}
+
+	if got, want := keys(slices.SortedFunc(lister.All(), ByName[*corev1.Pod])), []string{"default/a", "default/b", "default/c", "other/a"}; !slices.Equal(got, want) {
+		t.Errorf("All sorted by name = %v, want %v", got, want)
+	}
+	if got, want := keys(slices.SortedFunc(lister.All(), ByCreationTimestamp[*corev1.Pod])), []string{"default/c", "default/a", "default/b", "other/a"}; !slices.Equal(got, want) {
+		t.Errorf("All sorted by creation = %v, want %v", got, want)
+	}
+
+	onNode1 := func(p *corev1.Pod) bool { return p.Spec.NodeName == "node-1" }
+	if got, want := names(slices.Collect(lister.Filter(onNode1))), []string{"b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("Filter = %v, want %v", got, want)
+	}
+	if got, want := names(slices.Collect(lister.ByNamespace("default").All())), []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("ByNamespace.All = %v, want %v", got, want)
+	}
+	if got, want := names(slices.Collect(lister.ByNamespace("other").Filter(onNode1))), []string(nil); !slices.Equal(got, want) {
+		t.Errorf("ByNamespace.Filter = %v, want %v", got, want)
+	}
+	if got, want := keys(slices.SortedFunc(lister.ByNamespace(metav1.NamespaceAll).All(), ByName[*corev1.Pod])), []string{"default/a", "default/b", "default/c", "other/a"}; !slices.Equal(got, want) {
+		t.Errorf("ByNamespace(NamespaceAll).All = %v, want %v", got, want)
+	}
+	if got, want := keys(slices.SortedFunc(lister.ByNamespace(metav1.NamespaceAll).Filter(onNode1), ByName[*corev1.Pod])), []string{"default/b", "default/c"}; !slices.Equal(got, want) {
+		t.Errorf("ByNamespace(NamespaceAll).Filter = %v, want %v", got, want)
+	}
+
+	// Stopping iteration early must not visit the remaining objects.
+	var seen int
+	for range lister.All() {
+		seen++
+		break
+	}
+	if seen != 1 {
+		t.Errorf("visited %d objects after break, want 1", seen)
+	}
+}
+
+func TestFieldSelector(t *testing.T) {
+	pods := []*corev1.Pod{
+		indexTestPod("a", "node-1", map[string]string{"app": "web"}),
+		indexTestPod("b", "node-2", map[string]string{"app": "db"}),
+		indexTestPod("c", "", nil),
+	}
+	pods[0].Status.Phase = corev1.PodRunning
+	pods[1].Status.Phase = corev1.PodSucceeded
+	priority := int32(10)
+	pods[1].Spec.Priority = &priority
+	pods[2].Spec.HostNetwork = true
+
+	for _, tt := range []struct {
+		selector string
+		want     []string
+	}{
+		{"spec.nodeName=node-1", []string{"a"}},
+		{"spec.nodeName!=node-1", []string{"b", "c"}},
+		{"spec.nodeName=", []string{"c"}},
+		{"metadata.name=b", []string{"b"}},
+		{"metadata.namespace=default,status.phase!=Succeeded", []string{"a", "c"}},
+		{"metadata.labels.app=web", []string{"a"}},
+		{"spec.priority=10", []string{"b"}},
+		{"spec.hostNetwork=true", []string{"c"}},
+		{"spec.noSuchField=x", nil},
+		{"", []string{"a", "b", "c"}},
+	} {
+		match, err := FieldSelector[*corev1.Pod](tt.selector)
+		if err != nil {
+			t.Fatalf("FieldSelector(%q) failed: %v", tt.selector, err)
+		}
+		var got []*corev1.Pod
+		for _, p := range pods {
+			if match(p) {
+				got = append(got, p)
+			}
+		}
+		if names := names(got); !slices.Equal(names, tt.want) {
+			t.Errorf("FieldSelector(%q) matched %v, want %v", tt.selector, names, tt.want)
+		}
+	}
+
+	if _, err := FieldSelector[*corev1.Pod]("spec.nodeName=a=b"); err == nil {
+		t.Error("FieldSelector with invalid selector succeeded, want error")
+	}
+}
@@ -0,0 +1,74 @@
+package generic
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// metadataAccept asks the API server to return only the metadata of an object.
+	metadataAccept = "application/json;as=PartialObjectMetadata;g=meta.k8s.io;v=v1,application/json"
+	// metadataListAccept asks the API server to return only the metadata of
+	// the objects in a list.
+	metadataListAccept = "application/json;as=PartialObjectMetadataList;g=meta.k8s.io;v=v1,application/json"
+)
+
+// metadataCodecs decodes the PartialObjectMetadata and
+// PartialObjectMetadataList responses (and watch events) of metadata clients.
+var metadataCodecs = func() serializer.CodecFactory {
+	s := runtime.NewScheme()
+	metav1.AddToGroupVersion(s, schema.GroupVersion{Version: "v1"})
+	if err := metav1.AddMetaToScheme(s); err != nil {
+		panic(err)
+	}
+	return serializer.NewCodecFactory(s)
+}()
+
+// MetadataClient is a Client for only the metadata of objects of any
+// resource: names, labels, annotations, owner references, finalizers and so
+// on.
+//
+// The API server returns PartialObjectMetadata instead of full objects, so
+// informers and listers built from a MetadataClient cache a fraction of the
+// data. This is useful for controllers that only care about the metadata of
+// large or numerous objects like Secrets and ConfigMaps.
+//
+// List, Get, Watch, Inform, Patch, Delete and DeleteCollection are
+// supported. Create, Update and UpdateStatus are not, since the API server
+// does not accept PartialObjectMetadata request bodies.
+type MetadataClient = Client[*metav1.PartialObjectMetadata]
+
+// NewMetadataClient creates a MetadataClient for the given resource.
+func NewMetadataClient(gvr schema.GroupVersionResource, config *rest.Config) MetadataClient {
+	configCopy := rest.CopyConfig(config)
+	configCopy.NegotiatedSerializer = metadataCodecs.WithoutConversion()
+	c := NewClientGVR[*metav1.PartialObjectMetadata](gvr, configCopy)
+	c.metadataOnly = true
+	return c
+}
+
+// NewMetadataClientFor creates a MetadataClient for the resource of type T,
+// inferring its GroupVersionResource like NewClient.
+func NewMetadataClientFor[T runtime.Object](config *rest.Config) (MetadataClient, error) {
+	gvr, err := inferGVR[T](config)
+	if err != nil {
+		return MetadataClient{}, err
+	}
+	return NewMetadataClient(gvr, config), nil
+}
+
+// applyMetadataAccept asks for only object metadata in the response to req,
+// if this is a metadata client.
+func (c Client[T]) applyMetadataAccept(info RequestInfo, req *rest.Request) {
+	if !c.metadataOnly {
+		return
+	}
+	if info.Verb == "list" {
+		req.SetHeader("Accept", metadataListAccept)
+	} else {
+		req.SetHeader("Accept", metadataAccept)
+	}
+}
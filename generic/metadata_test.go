@@ -0,0 +1,98 @@
+package generic
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+const secretMetadataList = `{
+	"kind": "PartialObjectMetadataList",
+	"apiVersion": "meta.k8s.io/v1",
+	"metadata": {"resourceVersion": "1"},
+	"items": [
+		{"kind": "PartialObjectMetadata", "apiVersion": "meta.k8s.io/v1", "metadata": {"name": "a", "namespace": "default", "resourceVersion": "1", "labels": {"app": "web"}}},
+		{"kind": "PartialObjectMetadata", "apiVersion": "meta.k8s.io/v1", "metadata": {"name": "b", "namespace": "default", "resourceVersion": "1"}}
+	]
+}`
+
+// accept returns the Accept header of the last request for verb, one of
+// "get", "list" or "watch", made through transport.
+func accept(transport *mockTransport, verb string) string {
+	got := ""
+	for _, req := range transport.requests() {
+		v := "get"
+		switch {
+		case req.URL.Query().Get("watch") == "true":
+			v = "watch"
+		case strings.HasSuffix(req.URL.Path, "/secrets"):
+			v = "list"
+		}
+		if v == verb {
+			got = req.Header.Get("Accept")
+		}
+	}
+	return got
+}
+
+func TestMetadataClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	transport := &mockTransport{responses: map[string]mockResponse{
+		"GET /api/v1/secrets":                      {statusCode: http.StatusOK, body: secretMetadataList},
+		"GET /api/v1/namespaces/default/secrets/a": {statusCode: http.StatusOK, body: `{"kind": "PartialObjectMetadata", "apiVersion": "meta.k8s.io/v1", "metadata": {"name": "a", "namespace": "default", "finalizers": ["example.com/f"]}}`},
+		"WATCH": {statusCode: http.StatusOK, body: `{"type": "ADDED", "object": {"kind": "PartialObjectMetadata", "apiVersion": "meta.k8s.io/v1", "metadata": {"name": "c", "namespace": "default", "resourceVersion": "2"}}}` + "\n"},
+	}}
+	client := NewMetadataClient(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, &rest.Config{Host: "http://test", Transport: transport})
+
+	obj, err := client.Get(ctx, "default", "a", nil)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if obj.Name != "a" || len(obj.Finalizers) != 1 {
+		t.Errorf("unexpected object metadata: %+v", obj.ObjectMeta)
+	}
+	if got := accept(transport, "get"); got != metadataAccept {
+		t.Errorf("expected Get Accept %q, got %q", metadataAccept, got)
+	}
+
+	list, err := client.List(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list) != 2 || list[0].Labels["app"] != "web" {
+		t.Errorf("unexpected list: %v", list)
+	}
+	if got := accept(transport, "list"); got != metadataListAccept {
+		t.Errorf("expected List Accept %q, got %q", metadataListAccept, got)
+	}
+
+	informer, err := client.Inform(ctx, InformerHandler[*metav1.PartialObjectMetadata]{}, nil)
+	if err != nil {
+		t.Fatalf("Inform failed: %v", err)
+	}
+	lister := informer.Lister()
+	if obj, err := lister.ByNamespace("default").Get("a"); err != nil || obj.Labels["app"] != "web" {
+		t.Errorf("expected cached metadata for a, got %v, %v", obj, err)
+	}
+	// c is only delivered by the watch.
+	for {
+		if _, err := lister.ByNamespace("default").Get("c"); err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for watched object")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if got := accept(transport, "watch"); got != metadataAccept {
+		t.Errorf("expected Watch Accept %q, got %q", metadataAccept, got)
+	}
+}
@@ -0,0 +1,156 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// RequestInfo describes a single API request made by a Client.
+type RequestInfo struct {
+	// GVR is the resource the request was made against.
+	GVR schema.GroupVersionResource
+	// Verb is the Kubernetes API verb, e.g. "get", "list" or "update".
+	Verb string
+	// Subresource is the subresource the request was made against, if any.
+	Subresource string
+	// Namespace is the namespace of the request, if any.
+	Namespace string
+	// Name is the name of the object, for single-object requests.
+	Name string
+}
+
+// Metrics receives measurements of the API requests made by a Client.
+//
+// Implementations must be safe for concurrent use. See the metrics package
+// for a Prometheus implementation.
+type Metrics interface {
+	// ObserveRequest is called when each request completes, with the HTTP
+	// status code (or 0 if no response was received) and total latency.
+	ObserveRequest(info RequestInfo, code int, latency time.Duration)
+	// ObserveThrottle is called with the time each request spent waiting
+	// on the client-side rate limiter before it was sent.
+	ObserveThrottle(info RequestInfo, delay time.Duration)
+}
+
+// WithMetrics returns a copy of the client that reports every API request
+// it makes to m.
+func (c Client[T]) WithMetrics(m Metrics) Client[T] {
+	c.metrics = m
+	return c
+}
+
+// requestKey is the context key for the in-flight request's state.
+type requestKey struct{}
+
+// request is the state of a single in-flight API request.
+type request struct {
+	info    RequestInfo
+	metrics Metrics
+}
+
+// startRequest begins instrumenting an API request with metrics and tracing. The returned context
+// must be used to execute the request, and the returned function must be
+// called with its status code and error when it completes.
+func (c Client[T]) startRequest(ctx context.Context, info RequestInfo) (context.Context, func(code int, err error)) {
+	info.GVR = c.gvr
+	if c.metrics == nil && c.tracerProvider == nil {
+		return ctx, func(int, error) {}
+	}
+	ctx, span := c.startSpan(ctx, info)
+	if c.metrics != nil {
+		ctx = context.WithValue(ctx, requestKey{}, &request{info: info, metrics: c.metrics})
+	}
+	start := time.Now()
+	return ctx, func(code int, err error) {
+		code = responseCode(code, err)
+		if c.metrics != nil {
+			c.metrics.ObserveRequest(info, code, time.Since(start))
+		}
+		endSpan(span, code, err)
+	}
+}
+
+// do executes req as an instrumented API request, in dry-run and metadata-only
+// modes if the client is.
+func (c Client[T]) do(ctx context.Context, info RequestInfo, req *rest.Request) rest.Result {
+	c.applyDryRun(info, req)
+	c.applyMetadataAccept(info, req)
+	ctx, finish := c.startRequest(ctx, info)
+	result := req.Do(ctx)
+	var code int
+	result.StatusCode(&code)
+	finish(code, result.Error())
+	return result
+}
+
+// watch starts req as an instrumented watch request. Only establishing the
+// watch is measured.
+func (c Client[T]) watch(ctx context.Context, info RequestInfo, req *rest.Request) (watch.Interface, error) {
+	c.applyMetadataAccept(info, req)
+	ctx, finish := c.startRequest(ctx, info)
+	w, err := req.Watch(ctx)
+	finish(0, err)
+	return w, err
+}
+
+// responseCode returns the HTTP status code for a completed request.
+func responseCode(code int, err error) int {
+	if code != 0 {
+		return code
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return int(status.Status().Code)
+	}
+	if err == nil {
+		return http.StatusOK
+	}
+	return 0
+}
+
+// throttleRecorder wraps a rate limiter to report how long each request
+// waited on it to the request's Metrics.
+type throttleRecorder struct {
+	flowcontrol.RateLimiter
+}
+
+func (t throttleRecorder) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := t.RateLimiter.Wait(ctx)
+	if r, ok := ctx.Value(requestKey{}).(*request); ok {
+		r.metrics.ObserveThrottle(r.info, time.Since(start))
+	}
+	return err
+}
+
+// rateLimiterFor returns the rate limiter a REST client for config would use,
+// wrapped to record throttling delays. It mirrors the defaults applied by
+// rest.RESTClientFor.
+func rateLimiterFor(config *rest.Config) flowcontrol.RateLimiter {
+	rateLimiter := config.RateLimiter
+	if rateLimiter == nil {
+		qps := config.QPS
+		if config.QPS == 0.0 {
+			qps = rest.DefaultQPS
+		}
+		burst := config.Burst
+		if config.Burst == 0 {
+			burst = rest.DefaultBurst
+		}
+		if qps > 0 {
+			rateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+		}
+	}
+	if rateLimiter == nil {
+		return nil
+	}
+	return throttleRecorder{rateLimiter}
+}
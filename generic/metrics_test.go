@@ -0,0 +1,135 @@
+package generic
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// fakeMetrics records the requests reported to it.
+type fakeMetrics struct {
+	mu        sync.Mutex
+	requests  []RequestInfo
+	codes     []int
+	throttled []RequestInfo
+}
+
+func (f *fakeMetrics) ObserveRequest(info RequestInfo, code int, latency time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, info)
+	f.codes = append(f.codes, code)
+}
+
+func (f *fakeMetrics) ObserveThrottle(info RequestInfo, delay time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.throttled = append(f.throttled, info)
+}
+
+func TestWithMetrics(t *testing.T) {
+	ctx := context.Background()
+	m := &fakeMetrics{}
+	client := NewClientGVR[*corev1.Pod](
+		schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+		&rest.Config{
+			Host: "http://localhost",
+			Transport: &mockTransport{
+				responses: map[string]mockResponse{
+					"GET /api/v1/namespaces/default/pods/p": {
+						statusCode: 200,
+						body:       `{"kind":"Pod","apiVersion":"v1","metadata":{"name":"p","namespace":"default"}}`,
+					},
+					"PUT /api/v1/namespaces/default/pods/p/status": {
+						statusCode: 200,
+						body:       `{"kind":"Pod","apiVersion":"v1","metadata":{"name":"p","namespace":"default"}}`,
+					},
+				},
+			},
+		},
+	).WithMetrics(m)
+
+	pod, err := client.Get(ctx, "default", "p", nil)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if _, err := client.UpdateStatus(ctx, "default", pod, nil); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	if _, err := client.Get(ctx, "default", "missing", nil); err == nil {
+		t.Fatal("expected Get of missing pod to fail")
+	}
+
+	want := []struct {
+		info RequestInfo
+		code int
+	}{
+		{RequestInfo{Verb: "get", Namespace: "default", Name: "p"}, 200},
+		{RequestInfo{Verb: "update", Subresource: "status", Namespace: "default", Name: "p"}, 200},
+		{RequestInfo{Verb: "get", Namespace: "default", Name: "missing"}, 404},
+	}
+	if len(m.requests) != len(want) {
+		t.Fatalf("expected %d requests, got %d: %v", len(want), len(m.requests), m.requests)
+	}
+	for i, w := range want {
+		w.info.GVR = client.gvr
+		if m.requests[i] != w.info {
+			t.Errorf("request %d: expected %+v, got %+v", i, w.info, m.requests[i])
+		}
+		if m.codes[i] != w.code {
+			t.Errorf("request %d: expected code %d, got %d", i, w.code, m.codes[i])
+		}
+	}
+	if len(m.throttled) != len(want) {
+		t.Errorf("expected every request to pass the rate limiter, got %d", len(m.throttled))
+	}
+}
+
+func TestPodClientMetrics(t *testing.T) {
+	ctx := context.Background()
+	m := &fakeMetrics{}
+	client := NewClientGVR[*corev1.Pod](
+		schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+		&rest.Config{
+			Host: "http://localhost",
+			Transport: &mockTransport{
+				responses: map[string]mockResponse{
+					"POST /api/v1/namespaces/default/pods/p/binding":  {statusCode: 201},
+					"POST /api/v1/namespaces/default/pods/p/eviction": {statusCode: 201},
+				},
+			},
+		},
+	).WithMetrics(m).PodClient("default")
+
+	meta := metav1.ObjectMeta{Name: "p", Namespace: "default"}
+	if err := client.Bind(ctx, &corev1.Binding{ObjectMeta: meta}, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	if err := client.EvictV1(ctx, &policyv1.Eviction{ObjectMeta: meta}); err != nil {
+		t.Fatalf("EvictV1 failed: %v", err)
+	}
+
+	gvr := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+	want := []RequestInfo{
+		{GVR: gvr, Verb: "create", Subresource: "binding", Namespace: "default", Name: "p"},
+		{GVR: gvr, Verb: "create", Subresource: "eviction", Namespace: "default", Name: "p"},
+	}
+	if len(m.requests) != len(want) {
+		t.Fatalf("expected %d requests, got %d: %v", len(want), len(m.requests), m.requests)
+	}
+	for i, w := range want {
+		if m.requests[i] != w {
+			t.Errorf("request %d: expected %+v, got %+v", i, w, m.requests[i])
+		}
+		if m.codes[i] != 201 {
+			t.Errorf("request %d: expected code 201, got %d", i, m.codes[i])
+		}
+	}
+}
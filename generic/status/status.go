@@ -0,0 +1,326 @@
+// Package status computes a kstatus-style summary of whether an object has
+// finished reconciling.
+//
+// Compute works on any runtime.Object, including objects returned from
+// generic.Client.Get or a generic.Lister. Built-in rules are used for common
+// core, apps and batch kinds; other types (such as CRDs) are judged by their
+// status.observedGeneration and standard Ready, Reconciling and Stalled
+// conditions.
+//
+//	pod, err := client.Get(ctx, "default", "my-pod", nil)
+//	res, err := status.FromGet(pod, err)
+//	if res.Status == status.Current {
+//	    // ready
+//	}
+package status
+
+import (
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Status is the summarized state of an object.
+type Status string
+
+const (
+	// Current means the object is fully reconciled and ready.
+	Current Status = "Current"
+	// InProgress means the object is still being reconciled.
+	InProgress Status = "InProgress"
+	// Failed means reconciliation has failed and is unlikely to succeed
+	// without intervention.
+	Failed Status = "Failed"
+	// Terminating means the object is being deleted.
+	Terminating Status = "Terminating"
+	// NotFound means the object does not exist.
+	NotFound Status = "NotFound"
+)
+
+// Result is the computed status of an object, with a human-readable message
+// explaining it.
+type Result struct {
+	Status  Status
+	Message string
+}
+
+func (r Result) String() string {
+	if r.Message == "" {
+		return string(r.Status)
+	}
+	return fmt.Sprintf("%s: %s", r.Status, r.Message)
+}
+
+// FromGet computes the status of an object returned from a Get call (on a
+// generic.Client or generic.Lister), mapping a NotFound error to NotFound.
+// Any other error is returned as-is.
+func FromGet[T runtime.Object](obj T, err error) (Result, error) {
+	if apierrors.IsNotFound(err) {
+		return Result{Status: NotFound, Message: "object not found"}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return Compute(obj)
+}
+
+// Compute returns the status of obj. A nil obj is NotFound.
+func Compute(obj runtime.Object) (Result, error) {
+	if obj == nil || (reflect.ValueOf(obj).Kind() == reflect.Ptr && reflect.ValueOf(obj).IsNil()) {
+		return Result{Status: NotFound, Message: "object not found"}, nil
+	}
+
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	if m.GetDeletionTimestamp() != nil {
+		return Result{Status: Terminating, Message: "object is being deleted"}, nil
+	}
+
+	// Prefer typed rules for unstructured built-in kinds.
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		if typed, err := scheme.Scheme.New(u.GroupVersionKind()); err == nil {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err == nil {
+				obj = typed
+			}
+		}
+	}
+
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return deploymentStatus(o), nil
+	case *appsv1.StatefulSet:
+		return statefulSetStatus(o), nil
+	case *appsv1.DaemonSet:
+		return daemonSetStatus(o), nil
+	case *appsv1.ReplicaSet:
+		return replicaSetStatus(o), nil
+	case *batchv1.Job:
+		return jobStatus(o), nil
+	case *corev1.Pod:
+		return podStatus(o), nil
+	case *corev1.PersistentVolumeClaim:
+		return pvcStatus(o), nil
+	case *corev1.Service:
+		return serviceStatus(o), nil
+	}
+	return genericStatus(obj)
+}
+
+// generationPending returns a result if the controller has not yet observed the latest generation.
+func generationPending(generation, observed int64) (Result, bool) {
+	if observed < generation {
+		return Result{Status: InProgress, Message: fmt.Sprintf("observed generation %d is behind generation %d", observed, generation)}, true
+	}
+	return Result{}, false
+}
+
+func replicas(r *int32) int32 {
+	if r == nil {
+		return 1
+	}
+	return *r
+}
+
+func deploymentStatus(d *appsv1.Deployment) Result {
+	if res, ok := generationPending(d.Generation, d.Status.ObservedGeneration); ok {
+		return res
+	}
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded" {
+			return Result{Status: Failed, Message: c.Message}
+		}
+	}
+	want := replicas(d.Spec.Replicas)
+	switch {
+	case d.Status.UpdatedReplicas < want:
+		return Result{Status: InProgress, Message: fmt.Sprintf("updated: %d/%d", d.Status.UpdatedReplicas, want)}
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		return Result{Status: InProgress, Message: fmt.Sprintf("pending termination: %d", d.Status.Replicas-d.Status.UpdatedReplicas)}
+	case d.Status.AvailableReplicas < want:
+		return Result{Status: InProgress, Message: fmt.Sprintf("available: %d/%d", d.Status.AvailableReplicas, want)}
+	case d.Status.ReadyReplicas < want:
+		return Result{Status: InProgress, Message: fmt.Sprintf("ready: %d/%d", d.Status.ReadyReplicas, want)}
+	}
+	return Result{Status: Current, Message: fmt.Sprintf("deployment is available, replicas: %d", want)}
+}
+
+func statefulSetStatus(s *appsv1.StatefulSet) Result {
+	if res, ok := generationPending(s.Generation, s.Status.ObservedGeneration); ok {
+		return res
+	}
+	want := replicas(s.Spec.Replicas)
+	if s.Status.ReadyReplicas < want {
+		return Result{Status: InProgress, Message: fmt.Sprintf("ready: %d/%d", s.Status.ReadyReplicas, want)}
+	}
+	if s.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType {
+		if ru := s.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil && *ru.Partition > 0 {
+			updated := want - *ru.Partition
+			if s.Status.UpdatedReplicas < updated {
+				return Result{Status: InProgress, Message: fmt.Sprintf("partitioned rollout in progress, updated: %d/%d", s.Status.UpdatedReplicas, updated)}
+			}
+			return Result{Status: Current, Message: fmt.Sprintf("partitioned rollout complete, updated: %d", updated)}
+		}
+		if s.Status.UpdatedReplicas < want {
+			return Result{Status: InProgress, Message: fmt.Sprintf("updated: %d/%d", s.Status.UpdatedReplicas, want)}
+		}
+		if s.Status.CurrentRevision != s.Status.UpdateRevision {
+			return Result{Status: InProgress, Message: fmt.Sprintf("waiting for revision %s", s.Status.UpdateRevision)}
+		}
+	}
+	return Result{Status: Current, Message: fmt.Sprintf("all replicas ready: %d", want)}
+}
+
+func daemonSetStatus(d *appsv1.DaemonSet) Result {
+	if res, ok := generationPending(d.Generation, d.Status.ObservedGeneration); ok {
+		return res
+	}
+	want := d.Status.DesiredNumberScheduled
+	switch {
+	case d.Status.UpdatedNumberScheduled < want:
+		return Result{Status: InProgress, Message: fmt.Sprintf("updated: %d/%d", d.Status.UpdatedNumberScheduled, want)}
+	case d.Status.NumberAvailable < want:
+		return Result{Status: InProgress, Message: fmt.Sprintf("available: %d/%d", d.Status.NumberAvailable, want)}
+	case d.Status.NumberReady < want:
+		return Result{Status: InProgress, Message: fmt.Sprintf("ready: %d/%d", d.Status.NumberReady, want)}
+	}
+	return Result{Status: Current, Message: fmt.Sprintf("all replicas scheduled and ready: %d", want)}
+}
+
+func replicaSetStatus(r *appsv1.ReplicaSet) Result {
+	if res, ok := generationPending(r.Generation, r.Status.ObservedGeneration); ok {
+		return res
+	}
+	for _, c := range r.Status.Conditions {
+		if c.Type == appsv1.ReplicaSetReplicaFailure && c.Status == corev1.ConditionTrue {
+			return Result{Status: Failed, Message: c.Message}
+		}
+	}
+	want := replicas(r.Spec.Replicas)
+	switch {
+	case r.Status.AvailableReplicas < want:
+		return Result{Status: InProgress, Message: fmt.Sprintf("available: %d/%d", r.Status.AvailableReplicas, want)}
+	case r.Status.ReadyReplicas < want:
+		return Result{Status: InProgress, Message: fmt.Sprintf("ready: %d/%d", r.Status.ReadyReplicas, want)}
+	}
+	return Result{Status: Current, Message: fmt.Sprintf("all replicas ready: %d", want)}
+}
+
+func jobStatus(j *batchv1.Job) Result {
+	for _, c := range j.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobFailed:
+			return Result{Status: Failed, Message: c.Message}
+		case batchv1.JobComplete:
+			return Result{Status: Current, Message: "job completed"}
+		}
+	}
+	return Result{Status: InProgress, Message: fmt.Sprintf("job in progress, active: %d, succeeded: %d, failed: %d", j.Status.Active, j.Status.Succeeded, j.Status.Failed)}
+}
+
+func podStatus(p *corev1.Pod) Result {
+	switch p.Status.Phase {
+	case corev1.PodSucceeded:
+		return Result{Status: Current, Message: "pod succeeded"}
+	case corev1.PodFailed:
+		return Result{Status: Failed, Message: fmt.Sprintf("pod failed: %s", p.Status.Message)}
+	}
+	for _, statuses := range [][]corev1.ContainerStatus{p.Status.InitContainerStatuses, p.Status.ContainerStatuses} {
+		for _, cs := range statuses {
+			if w := cs.State.Waiting; w != nil {
+				switch w.Reason {
+				case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "CreateContainerConfigError", "InvalidImageName":
+					return Result{Status: Failed, Message: fmt.Sprintf("container %s: %s", cs.Name, w.Reason)}
+				}
+			}
+		}
+	}
+	if p.Status.Phase == corev1.PodRunning {
+		for _, c := range p.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+				return Result{Status: Current, Message: "pod is ready"}
+			}
+		}
+		return Result{Status: InProgress, Message: "pod is running but not ready"}
+	}
+	return Result{Status: InProgress, Message: fmt.Sprintf("pod phase: %s", p.Status.Phase)}
+}
+
+func pvcStatus(p *corev1.PersistentVolumeClaim) Result {
+	switch p.Status.Phase {
+	case corev1.ClaimBound:
+		return Result{Status: Current, Message: "claim is bound"}
+	case corev1.ClaimLost:
+		return Result{Status: Failed, Message: "claim lost its volume"}
+	}
+	return Result{Status: InProgress, Message: fmt.Sprintf("claim phase: %s", p.Status.Phase)}
+}
+
+func serviceStatus(s *corev1.Service) Result {
+	if s.Spec.Type == corev1.ServiceTypeLoadBalancer && len(s.Status.LoadBalancer.Ingress) == 0 {
+		return Result{Status: InProgress, Message: "waiting for load balancer ingress"}
+	}
+	return Result{Status: Current, Message: "service is ready"}
+}
+
+// genericStatus applies observedGeneration and condition rules to arbitrary objects.
+func genericStatus(obj runtime.Object) (Result, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to convert %T to unstructured: %w", obj, err)
+		}
+		u = &unstructured.Unstructured{Object: m}
+	}
+
+	if observed, found, err := unstructured.NestedInt64(u.Object, "status", "observedGeneration"); err == nil && found {
+		if res, ok := generationPending(u.GetGeneration(), observed); ok {
+			return res, nil
+		}
+	}
+
+	conds, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	byType := map[string]map[string]any{}
+	for _, c := range conds {
+		if cm, ok := c.(map[string]any); ok {
+			if t, ok := cm["type"].(string); ok {
+				byType[t] = cm
+			}
+		}
+	}
+	isTrue := func(t string) (map[string]any, bool) {
+		c, ok := byType[t]
+		return c, ok && c["status"] == "True"
+	}
+	message := func(c map[string]any) string {
+		msg, _ := c["message"].(string)
+		return msg
+	}
+
+	if c, ok := isTrue("Stalled"); ok {
+		return Result{Status: Failed, Message: message(c)}, nil
+	}
+	if c, ok := isTrue("Reconciling"); ok {
+		return Result{Status: InProgress, Message: message(c)}, nil
+	}
+	if c, ok := byType["Ready"]; ok {
+		if c["status"] == "True" {
+			return Result{Status: Current, Message: message(c)}, nil
+		}
+		return Result{Status: InProgress, Message: message(c)}, nil
+	}
+	return Result{Status: Current, Message: "resource is current"}, nil
+}
@@ -0,0 +1,247 @@
+package status
+
+import (
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestCompute(t *testing.T) {
+	now := metav1.Now()
+	for _, tt := range []struct {
+		name string
+		obj  runtime.Object
+		want Status
+	}{{
+		name: "nil pod",
+		obj:  (*corev1.Pod)(nil),
+		want: NotFound,
+	}, {
+		name: "terminating",
+		obj:  &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+		want: Terminating,
+	}, {
+		name: "deployment generation not observed",
+		obj: &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr(int32(1))},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1},
+		},
+		want: InProgress,
+	}, {
+		name: "deployment rolling out",
+		obj: &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr(int32(3))},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 4, UpdatedReplicas: 3, ReadyReplicas: 3, AvailableReplicas: 3},
+		},
+		want: InProgress,
+	}, {
+		name: "deployment progress deadline exceeded",
+		obj: &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 1},
+			Status: appsv1.DeploymentStatus{ObservedGeneration: 1, Conditions: []appsv1.DeploymentCondition{{
+				Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+			}}},
+		},
+		want: Failed,
+	}, {
+		name: "deployment available",
+		obj: &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr(int32(3))},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3, AvailableReplicas: 3},
+		},
+		want: Current,
+	}, {
+		name: "statefulset revision pending",
+		obj: &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:       ptr(int32(2)),
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+			},
+			Status: appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 2, CurrentRevision: "a", UpdateRevision: "b"},
+		},
+		want: InProgress,
+	}, {
+		name: "statefulset ready",
+		obj: &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:       ptr(int32(2)),
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+			},
+			Status: appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 2, CurrentRevision: "b", UpdateRevision: "b"},
+		},
+		want: Current,
+	}, {
+		name: "daemonset not ready",
+		obj:  &appsv1.DaemonSet{Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3, NumberReady: 2}},
+		want: InProgress,
+	}, {
+		name: "replicaset failure",
+		obj: &appsv1.ReplicaSet{Status: appsv1.ReplicaSetStatus{Conditions: []appsv1.ReplicaSetCondition{{
+			Type: appsv1.ReplicaSetReplicaFailure, Status: corev1.ConditionTrue,
+		}}}},
+		want: Failed,
+	}, {
+		name: "job running",
+		obj:  &batchv1.Job{Status: batchv1.JobStatus{Active: 1}},
+		want: InProgress,
+	}, {
+		name: "job complete",
+		obj:  &batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}}},
+		want: Current,
+	}, {
+		name: "job failed",
+		obj:  &batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}}},
+		want: Failed,
+	}, {
+		name: "pod pending",
+		obj:  &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}},
+		want: InProgress,
+	}, {
+		name: "pod crashlooping",
+		obj: &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+			Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}}},
+		want: Failed,
+	}, {
+		name: "pod ready",
+		obj: &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{
+			Type: corev1.PodReady, Status: corev1.ConditionTrue,
+		}}}},
+		want: Current,
+	}, {
+		name: "pvc pending",
+		obj:  &corev1.PersistentVolumeClaim{Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+		want: InProgress,
+	}, {
+		name: "pvc bound",
+		obj:  &corev1.PersistentVolumeClaim{Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}},
+		want: Current,
+	}, {
+		name: "load balancer pending",
+		obj:  &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}},
+		want: InProgress,
+	}, {
+		name: "load balancer ready",
+		obj: &corev1.Service{
+			Spec:   corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}}},
+		},
+		want: Current,
+	}, {
+		name: "cluster ip service",
+		obj:  &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+		want: Current,
+	}, {
+		name: "configmap without status",
+		obj:  &corev1.ConfigMap{},
+		want: Current,
+	}, {
+		name: "typed object with metav1 conditions",
+		obj: &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Generation: 1},
+			Status: policyv1.PodDisruptionBudgetStatus{ObservedGeneration: 1, Conditions: []metav1.Condition{{
+				Type: "Ready", Status: metav1.ConditionFalse, Message: "not yet",
+			}}},
+		},
+		want: InProgress,
+	}, {
+		name: "crd generation not observed",
+		obj:  crd(2, map[string]any{"observedGeneration": int64(1)}),
+		want: InProgress,
+	}, {
+		name: "crd ready",
+		obj: crd(2, map[string]any{
+			"observedGeneration": int64(2),
+			"conditions":         []any{map[string]any{"type": "Ready", "status": "True"}},
+		}),
+		want: Current,
+	}, {
+		name: "crd not ready",
+		obj: crd(1, map[string]any{
+			"conditions": []any{map[string]any{"type": "Ready", "status": "False", "message": "waiting"}},
+		}),
+		want: InProgress,
+	}, {
+		name: "crd reconciling",
+		obj: crd(1, map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "True"},
+				map[string]any{"type": "Reconciling", "status": "True"},
+			},
+		}),
+		want: InProgress,
+	}, {
+		name: "crd stalled",
+		obj: crd(1, map[string]any{
+			"conditions": []any{map[string]any{"type": "Stalled", "status": "True", "message": "bad config"}},
+		}),
+		want: Failed,
+	}, {
+		name: "unstructured built-in uses typed rules",
+		obj: &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolumeClaim",
+			"metadata":   map[string]any{"name": "claim"},
+			"status":     map[string]any{"phase": "Lost"},
+		}},
+		want: Failed,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compute(tt.obj)
+			if err != nil {
+				t.Fatalf("Compute failed: %v", err)
+			}
+			if got.Status != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func crd(generation int64, status map[string]any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]any{"name": "w", "generation": generation},
+		"status":     status,
+	}}
+	return u
+}
+
+func TestFromGet(t *testing.T) {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "p")
+	got, err := FromGet[*corev1.Pod](nil, notFound)
+	if err != nil {
+		t.Fatalf("FromGet failed: %v", err)
+	}
+	if got.Status != NotFound {
+		t.Errorf("expected NotFound, got %s", got)
+	}
+
+	other := errors.New("connection refused")
+	if _, err := FromGet[*corev1.Pod](nil, other); !errors.Is(err, other) {
+		t.Errorf("expected error to be returned, got %v", err)
+	}
+
+	got, err = FromGet(&corev1.PersistentVolumeClaim{Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}}, nil)
+	if err != nil {
+		t.Fatalf("FromGet failed: %v", err)
+	}
+	if got.Status != Current {
+		t.Errorf("expected Current, got %s", got)
+	}
+}
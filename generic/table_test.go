@@ -0,0 +1,179 @@
+package generic
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+const podTable = `{
+	"kind": "Table",
+	"apiVersion": "meta.k8s.io/v1",
+	"metadata": {},
+	"columnDefinitions": [
+		{"name": "Name", "type": "string", "format": "name", "priority": 0},
+		{"name": "Ready", "type": "string", "priority": 0},
+		{"name": "Restarts", "type": "integer", "priority": 0},
+		{"name": "Node", "type": "string", "priority": 1}
+	],
+	"rows": [
+		{
+			"cells": ["web-1", "1/1", 3, "node-a"],
+			"object": {"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "web-1", "namespace": "default"}, "spec": {"nodeName": "node-a"}}
+		},
+		{
+			"cells": ["db-0", "0/1", 0, null],
+			"object": {"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "db-0", "namespace": "prod"}}
+		}
+	]
+}`
+
+const podMetadataTable = `{
+	"kind": "Table",
+	"apiVersion": "meta.k8s.io/v1",
+	"metadata": {},
+	"columnDefinitions": [{"name": "Name", "type": "string", "format": "name", "priority": 0}],
+	"rows": [
+		{
+			"cells": ["web-1"],
+			"object": {"kind": "PartialObjectMetadata", "apiVersion": "meta.k8s.io/v1", "metadata": {"name": "web-1", "namespace": "default"}}
+		}
+	]
+}`
+
+// newTableClient returns a client whose server serves bodies, keyed by path,
+// and fails the test if a request doesn't ask for a Table.
+func newTableClient(t *testing.T, bodies map[string]string) (Client[*corev1.Pod], *mockTransport) {
+	transport := &mockTransport{responses: map[string]mockResponse{}}
+	for path, body := range bodies {
+		transport.responses["GET "+path] = mockResponse{statusCode: http.StatusOK, body: body}
+	}
+	t.Cleanup(func() {
+		for _, req := range transport.requests() {
+			if accept := req.Header.Get("Accept"); !strings.Contains(accept, "as=Table") {
+				t.Errorf("expected Table Accept header, got %q", accept)
+			}
+		}
+	})
+	return NewClientGVR[*corev1.Pod](
+		schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+		&rest.Config{Host: "http://localhost", Transport: transport},
+	), transport
+}
+
+// lastQuery returns the query of the last request made through transport.
+func lastQuery(transport *mockTransport) string {
+	reqs := transport.requests()
+	return reqs[len(reqs)-1].URL.RawQuery
+}
+
+func TestListTable(t *testing.T) {
+	client, transport := newTableClient(t, map[string]string{"/api/v1/pods": podTable})
+
+	table, err := client.WithTableObjects().ListTable(context.Background(), "", nil)
+	if err != nil {
+		t.Fatalf("ListTable failed: %v", err)
+	}
+	if q := lastQuery(transport); q != "includeObject=Object" {
+		t.Errorf("expected includeObject=Object, got %q", q)
+	}
+	if len(table.Rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(table.Rows))
+	}
+
+	pods, err := TableObjects[*corev1.Pod](table)
+	if err != nil {
+		t.Fatalf("TableObjects failed: %v", err)
+	}
+	if pods[0].Name != "web-1" || pods[0].Spec.NodeName != "node-a" || pods[1].Name != "db-0" {
+		t.Errorf("unexpected objects: %s/%s, %s", pods[0].Name, pods[0].Spec.NodeName, pods[1].Name)
+	}
+	if _, err := TableObject[*corev1.Pod](table, 2); err == nil {
+		t.Error("expected error for out of range row")
+	}
+}
+
+func TestGetTableMetadataOnly(t *testing.T) {
+	client, transport := newTableClient(t, map[string]string{"/api/v1/namespaces/default/pods/web-1": podMetadataTable})
+
+	table, err := client.GetTable(context.Background(), "default", "web-1", nil)
+	if err != nil {
+		t.Fatalf("GetTable failed: %v", err)
+	}
+	if len(table.Rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(table.Rows))
+	}
+	if q := lastQuery(transport); q != "" {
+		t.Errorf("expected no includeObject by default, got %q", q)
+	}
+	if _, err := TableObject[*corev1.Pod](table, 0); err == nil {
+		t.Error("expected error accessing a metadata-only row as a Pod")
+	}
+
+	if _, err := client.GetTable(context.Background(), "default", "missing", nil); err == nil {
+		t.Error("expected error for missing object")
+	}
+}
+
+func TestListTableNotATable(t *testing.T) {
+	client, _ := newTableClient(t, map[string]string{"/api/v1/pods": `{"kind":"PodList","apiVersion":"v1","items":[]}`})
+	if _, err := client.ListTable(context.Background(), "", nil); err == nil {
+		t.Error("expected error when the server does not return a Table")
+	}
+}
+
+func TestTablePrint(t *testing.T) {
+	client, _ := newTableClient(t, map[string]string{"/api/v1/pods": podTable})
+	table, err := client.ListTable(context.Background(), "", nil)
+	if err != nil {
+		t.Fatalf("ListTable failed: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		opts PrintOptions
+		want string
+	}{{
+		name: "default",
+		want: "" +
+			"NAME    READY   RESTARTS\n" +
+			"web-1   1/1     3\n" +
+			"db-0    0/1     0\n",
+	}, {
+		name: "wide",
+		opts: PrintOptions{Wide: true},
+		want: "" +
+			"NAME    READY   RESTARTS   NODE\n" +
+			"web-1   1/1     3          node-a\n" +
+			"db-0    0/1     0          <none>\n",
+	}, {
+		name: "no headers",
+		opts: PrintOptions{NoHeaders: true},
+		want: "" +
+			"web-1   1/1   3\n" +
+			"db-0    0/1   0\n",
+	}, {
+		name: "with namespace",
+		opts: PrintOptions{WithNamespace: true},
+		want: "" +
+			"NAMESPACE   NAME    READY   RESTARTS\n" +
+			"default     web-1   1/1     3\n" +
+			"prod        db-0    0/1     0\n",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := PrintTable(&buf, table, tt.opts); err != nil {
+				t.Fatalf("PrintTable failed: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("PrintTable() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
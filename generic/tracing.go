@@ -0,0 +1,64 @@
+package generic
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of spans created by a Client.
+const tracerName = "github.com/imjasonh/client-go2/generic"
+
+// WithTracerProvider returns a copy of the client that records an
+// OpenTelemetry span for every API request it makes, as a child of any span
+// in the request's context.
+func (c Client[T]) WithTracerProvider(tp trace.TracerProvider) Client[T] {
+	c.tracerProvider = tp
+	return c
+}
+
+// startSpan starts a client span for an API request, if tracing is enabled.
+func (c Client[T]) startSpan(ctx context.Context, info RequestInfo) (context.Context, trace.Span) {
+	if c.tracerProvider == nil {
+		return ctx, nil
+	}
+	name := info.Verb + " " + info.GVR.Resource
+	if info.Subresource != "" {
+		name += "/" + info.Subresource
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("k8s.verb", info.Verb),
+		attribute.String("k8s.group", info.GVR.Group),
+		attribute.String("k8s.version", info.GVR.Version),
+		attribute.String("k8s.resource", info.GVR.Resource),
+	}
+	if info.Subresource != "" {
+		attrs = append(attrs, attribute.String("k8s.subresource", info.Subresource))
+	}
+	if info.Namespace != "" {
+		attrs = append(attrs, attribute.String("k8s.namespace.name", info.Namespace))
+	}
+	if info.Name != "" {
+		attrs = append(attrs, attribute.String("k8s.object.name", info.Name))
+	}
+	return c.tracerProvider.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// endSpan records the outcome of an API request on span and ends it.
+func endSpan(span trace.Span, code int, err error) {
+	if span == nil {
+		return
+	}
+	if code != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", code))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
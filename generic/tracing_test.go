@@ -0,0 +1,89 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	client := NewClientGVR[*corev1.Pod](
+		schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+		&rest.Config{
+			Host: "http://localhost",
+			Transport: &mockTransport{
+				responses: map[string]mockResponse{
+					"GET /api/v1/namespaces/default/pods/p": {
+						statusCode: 200,
+						body:       `{"kind":"Pod","apiVersion":"v1","metadata":{"name":"p","namespace":"default"}}`,
+					},
+				},
+			},
+		},
+	).WithTracerProvider(tp)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	if _, err := client.Get(ctx, "default", "p", nil); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if _, err := client.Get(ctx, "default", "missing", nil); err == nil {
+		t.Fatal("expected Get of missing pod to fail")
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	for i, want := range []struct {
+		name   string
+		code   int64
+		status codes.Code
+	}{
+		{"get pods", 200, codes.Unset},
+		{"get pods", 404, codes.Error},
+	} {
+		span := spans[i]
+		if span.Name != want.name {
+			t.Errorf("span %d: expected name %q, got %q", i, want.name, span.Name)
+		}
+		if span.SpanKind != trace.SpanKindClient {
+			t.Errorf("span %d: expected client span, got %v", i, span.SpanKind)
+		}
+		if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %d: expected parent %v, got %v", i, parent.SpanContext().SpanID(), span.Parent.SpanID())
+		}
+		if span.Status.Code != want.status {
+			t.Errorf("span %d: expected status %v, got %v", i, want.status, span.Status.Code)
+		}
+		attrs := attribute.NewSet(span.Attributes...)
+		if v, _ := attrs.Value("http.response.status_code"); v.AsInt64() != want.code {
+			t.Errorf("span %d: expected status code %d, got %d", i, want.code, v.AsInt64())
+		}
+		if v, _ := attrs.Value("k8s.namespace.name"); v.AsString() != "default" {
+			t.Errorf("span %d: expected namespace default, got %q", i, v.AsString())
+		}
+		if v, _ := attrs.Value("k8s.resource"); v.AsString() != "pods" {
+			t.Errorf("span %d: expected resource pods, got %q", i, v.AsString())
+		}
+	}
+}
+
+func TestNoTracerProvider(t *testing.T) {
+	client := Client[*corev1.Pod]{}
+	ctx := context.Background()
+	got, span := client.startSpan(ctx, RequestInfo{Verb: "get"})
+	if got != ctx || span != nil {
+		t.Error("expected no span without a tracer provider")
+	}
+}
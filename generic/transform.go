@@ -0,0 +1,27 @@
+package generic
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// StripManagedFields is an InformOptions.Transform that removes
+// metadata.managedFields and the kubectl last-applied-configuration
+// annotation from objects before they are cached. These are often the
+// largest parts of an object's metadata and are rarely needed by
+// controllers.
+func StripManagedFields[T runtime.Object](obj T) (T, error) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return obj, err
+	}
+	m.SetManagedFields(nil)
+	if annotations := m.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+			delete(annotations, corev1.LastAppliedConfigAnnotation)
+			m.SetAnnotations(annotations)
+		}
+	}
+	return obj, nil
+}
@@ -0,0 +1,164 @@
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestStripManagedFields(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "p",
+		Annotations: map[string]string{
+			corev1.LastAppliedConfigAnnotation: `{"apiVersion":"v1"}`,
+			"keep":                             "me",
+		},
+		ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+	}}
+	got, err := StripManagedFields(pod)
+	if err != nil {
+		t.Fatalf("StripManagedFields failed: %v", err)
+	}
+	if got.ManagedFields != nil {
+		t.Errorf("expected managedFields to be removed, got %v", got.ManagedFields)
+	}
+	if _, ok := got.Annotations[corev1.LastAppliedConfigAnnotation]; ok {
+		t.Error("expected last-applied annotation to be removed")
+	}
+	if got.Annotations["keep"] != "me" {
+		t.Errorf("expected other annotations to be kept, got %v", got.Annotations)
+	}
+}
+
+func TestInformTransform(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := newWaitTestClient(listWatch(`{
+		"kind": "PodList",
+		"apiVersion": "v1",
+		"metadata": {"resourceVersion": "1"},
+		"items": [{
+			"kind": "Pod",
+			"apiVersion": "v1",
+			"metadata": {
+				"name": "p",
+				"namespace": "default",
+				"resourceVersion": "1",
+				"annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+				"managedFields": [{"manager": "kubectl", "operation": "Apply", "fieldsType": "FieldsV1", "fieldsV1": {"f:spec": {}}}]
+			}
+		}]
+	}`, ""))
+
+	added := make(chan *corev1.Pod, 1)
+	informer, err := client.Inform(ctx, InformerHandler[*corev1.Pod]{
+		OnAdd: func(_ string, pod *corev1.Pod) { added <- pod },
+	}, &InformOptions[*corev1.Pod]{Transform: StripManagedFields[*corev1.Pod]})
+	if err != nil {
+		t.Fatalf("Inform failed: %v", err)
+	}
+	pod, err := informer.Lister().ByNamespace("default").Get("p")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(pod.ManagedFields) != 0 || len(pod.Annotations) != 0 {
+		t.Errorf("expected cached object to be transformed, got %+v", pod.ObjectMeta)
+	}
+	select {
+	case pod := <-added:
+		if len(pod.ManagedFields) != 0 {
+			t.Errorf("expected handler to receive the transformed object, got %+v", pod.ObjectMeta)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for OnAdd")
+	}
+}
+
+// syntheticPods returns the JSON encodings of n Pods with managedFields and
+// last-applied annotations like those written by kubectl apply and
+// controllers.
+func syntheticPods(b *testing.B, n int) [][]byte {
+	fields := metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{".":{},"f:app":{}}},"f:spec":{"f:containers":{"k:{\"name\":\"app\"}":{".":{},"f:image":{},"f:imagePullPolicy":{},"f:name":{},"f:resources":{},"f:terminationMessagePath":{},"f:terminationMessagePolicy":{}}},"f:dnsPolicy":{},"f:enableServiceLinks":{},"f:restartPolicy":{},"f:schedulerName":{},"f:securityContext":{},"f:terminationGracePeriodSeconds":{}}}`)}
+	var out [][]byte
+	for i := 0; i < n; i++ {
+		pod := &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("pod-%d", i),
+				Namespace: "default",
+				Labels:    map[string]string{"app": "web"},
+				Annotations: map[string]string{
+					corev1.LastAppliedConfigAnnotation: strings.Repeat("x", 1024),
+				},
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{Manager: "kubectl-client-side-apply", Operation: metav1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1", FieldsV1: &fields},
+					{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1", FieldsV1: &fields},
+					{Manager: "kubelet", Operation: metav1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1", FieldsV1: &fields, Subresource: "status"},
+				},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx"}}},
+		}
+		data, err := json.Marshal(pod)
+		if err != nil {
+			b.Fatal(err)
+		}
+		out = append(out, data)
+	}
+	return out
+}
+
+// BenchmarkStripManagedFields reports the heap retained by a cache of 10,000
+// Pods with and without the StripManagedFields transform, as cache-bytes/op.
+func BenchmarkStripManagedFields(b *testing.B) {
+	pods := syntheticPods(b, 10000)
+	for _, bc := range []struct {
+		name      string
+		transform func(*corev1.Pod) (*corev1.Pod, error)
+	}{
+		{"none", nil},
+		{"StripManagedFields", StripManagedFields[*corev1.Pod]},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var retained uint64
+			for i := 0; i < b.N; i++ {
+				var before, after runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+
+				store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+				for _, data := range pods {
+					pod := &corev1.Pod{}
+					if err := json.Unmarshal(data, pod); err != nil {
+						b.Fatal(err)
+					}
+					if bc.transform != nil {
+						var err error
+						if pod, err = bc.transform(pod); err != nil {
+							b.Fatal(err)
+						}
+					}
+					if err := store.Add(pod); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+				if after.HeapAlloc > before.HeapAlloc {
+					retained += after.HeapAlloc - before.HeapAlloc
+				}
+				runtime.KeepAlive(store)
+			}
+			b.ReportMetric(float64(retained)/float64(b.N), "cache-bytes/op")
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+// WaitFor blocks until condition returns true for the named object, the
+// condition returns an error, or ctx is done.
+//
+// The object is observed with a list+watch scoped to the single name, so
+// changes are delivered as they happen rather than by polling. Dropped watch
+// connections (including expired resource versions) are resumed transparently.
+//
+// The condition is not called while the object does not exist; if it is
+// deleted while waiting, WaitFor keeps waiting for it to be recreated.
+//
+// WaitFor always returns the last observed version of the object, including
+// when ctx times out, so that callers can report why the condition was never
+// met.
+func (c Client[T]) WaitFor(ctx context.Context, namespace, name string, condition func(T) (bool, error)) (T, error) {
+	var last T
+	_, err := c.untilWithSync(ctx, namespace, name, nil, func(event watch.Event) (bool, error) {
+		switch event.Type {
+		case watch.Added, watch.Modified:
+			t, ok := event.Object.(T)
+			if !ok {
+				return false, fmt.Errorf("expected type %T, got %T", last, event.Object)
+			}
+			last = t
+			return condition(t)
+		}
+		return false, nil
+	})
+	return last, c.waitErr(ctx, namespace, name, err)
+}
+
+// WaitForDeletion blocks until the named object no longer exists or ctx is
+// done. It returns immediately if the object does not exist when called.
+//
+// Like WaitFor, it returns the last observed version of the object, so that a
+// timeout can be diagnosed (for example by inspecting its finalizers).
+func (c Client[T]) WaitForDeletion(ctx context.Context, namespace, name string) (T, error) {
+	var last T
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+	_, err := c.untilWithSync(ctx, namespace, name, func(store cache.Store) (bool, error) {
+		obj, exists, err := store.GetByKey(key)
+		if err != nil {
+			return false, err
+		}
+		if t, ok := obj.(T); ok {
+			last = t
+		}
+		return !exists, nil
+	}, func(event watch.Event) (bool, error) {
+		if t, ok := event.Object.(T); ok {
+			last = t
+		}
+		return event.Type == watch.Deleted, nil
+	})
+	return last, c.waitErr(ctx, namespace, name, err)
+}
+
+// untilWithSync watches the single named object until the conditions are met.
+func (c Client[T]) untilWithSync(ctx context.Context, namespace, name string, precondition watchtools.PreconditionFunc, conditions ...watchtools.ConditionFunc) (*watch.Event, error) {
+	lw := c.listWatch(namespace, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	var zero T
+	return watchtools.UntilWithSync(ctx, lw, zero, precondition, conditions...)
+}
+
+// waitErr annotates errors returned while waiting with the object being waited on.
+func (c Client[T]) waitErr(ctx context.Context, namespace, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if wait.Interrupted(err) && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return fmt.Errorf("waiting for %s %s/%s: %w", c.gvr.Resource, namespace, name, err)
+}
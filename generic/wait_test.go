@@ -0,0 +1,132 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// listWatch returns a mockTransport that serves list to every list request
+// and watch to every watch request.
+func listWatch(list, watch string) *mockTransport {
+	return &mockTransport{responses: map[string]mockResponse{
+		"GET":   {statusCode: http.StatusOK, body: list},
+		"WATCH": {statusCode: http.StatusOK, body: watch},
+	}}
+}
+
+func newWaitTestClient(transport http.RoundTripper) Client[*corev1.Pod] {
+	return NewClientGVR[*corev1.Pod](
+		schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+		&rest.Config{Host: "http://test", Transport: transport},
+	)
+}
+
+const pendingPodList = `{
+	"kind": "PodList",
+	"apiVersion": "v1",
+	"metadata": {"resourceVersion": "1"},
+	"items": [
+		{"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "p", "namespace": "default", "resourceVersion": "1"}, "status": {"phase": "Pending"}}
+	]
+}`
+
+func TestWaitFor(t *testing.T) {
+	client := newWaitTestClient(listWatch(pendingPodList,
+		`{"type": "MODIFIED", "object": {"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "p", "namespace": "default", "resourceVersion": "2"}, "status": {"phase": "Running"}}}`+"\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var seen []corev1.PodPhase
+	pod, err := client.WaitFor(ctx, "default", "p", func(pod *corev1.Pod) (bool, error) {
+		seen = append(seen, pod.Status.Phase)
+		return pod.Status.Phase == corev1.PodRunning, nil
+	})
+	if err != nil {
+		t.Fatalf("WaitFor failed: %v", err)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		t.Errorf("expected Running pod, got %s", pod.Status.Phase)
+	}
+	if len(seen) != 2 || seen[0] != corev1.PodPending {
+		t.Errorf("expected condition to see Pending then Running, got %v", seen)
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	// The watch stream ends without the pod becoming ready, so WaitFor has
+	// to keep re-establishing the watch until the context expires.
+	client := newWaitTestClient(listWatch(pendingPodList, ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	pod, err := client.WaitFor(ctx, "default", "p", func(pod *corev1.Pod) (bool, error) {
+		return pod.Status.Phase == corev1.PodRunning, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if pod == nil || pod.Status.Phase != corev1.PodPending {
+		t.Errorf("expected last observed Pending pod, got %v", pod)
+	}
+}
+
+func TestWaitForConditionError(t *testing.T) {
+	client := newWaitTestClient(listWatch(pendingPodList, ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wantErr := errors.New("pod failed")
+	if _, err := client.WaitFor(ctx, "default", "p", func(*corev1.Pod) (bool, error) {
+		return false, wantErr
+	}); !errors.Is(err, wantErr) {
+		t.Errorf("expected condition error, got %v", err)
+	}
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	client := newWaitTestClient(listWatch(pendingPodList,
+		`{"type": "DELETED", "object": {"kind": "Pod", "apiVersion": "v1", "metadata": {"name": "p", "namespace": "default", "resourceVersion": "2", "finalizers": ["example.com/cleanup"]}}}`+"\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.WaitForDeletion(ctx, "default", "p"); err != nil {
+		t.Fatalf("WaitForDeletion failed: %v", err)
+	}
+}
+
+func TestWaitForDeletionNotFound(t *testing.T) {
+	client := newWaitTestClient(listWatch(`{"kind": "PodList", "apiVersion": "v1", "metadata": {"resourceVersion": "1"}, "items": []}`, ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.WaitForDeletion(ctx, "default", "p"); err != nil {
+		t.Fatalf("WaitForDeletion failed: %v", err)
+	}
+}
+
+func TestWaitForDeletionTimeout(t *testing.T) {
+	client := newWaitTestClient(listWatch(pendingPodList, ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	pod, err := client.WaitForDeletion(ctx, "default", "p")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if pod == nil || pod.Name != "p" {
+		t.Errorf("expected last observed pod, got %v", pod)
+	}
+}
@@ -75,7 +75,7 @@ func main() {
 		OnError: func(obj any, err error) {
 			log.Fatalf("[LABELED] Error: %v", err)
 		},
-	}, &generic.InformOptions{
+	}, &generic.InformOptions[*corev1.ConfigMap]{
 		ListOptions: metav1.ListOptions{
 			LabelSelector: "test=example",
 		},
@@ -101,7 +101,7 @@ func main() {
 		OnError: func(obj any, err error) {
 			log.Fatalf("[RUNNING] Error: %v", err)
 		},
-	}, &generic.InformOptions{
+	}, &generic.InformOptions[*corev1.Pod]{
 		ListOptions: metav1.ListOptions{
 			FieldSelector: "status.phase=Running",
 		},
@@ -125,7 +125,7 @@ func main() {
 		OnError: func(obj any, err error) {
 			log.Fatalf("[RESYNC] Error: %v", err)
 		},
-	}, &generic.InformOptions{
+	}, &generic.InformOptions[*corev1.ConfigMap]{
 		ListOptions: metav1.ListOptions{
 			LabelSelector: "special=resync-test",
 		},
@@ -245,11 +245,12 @@ func main() {
 	log.Println("\nDEMONSTRATING LISTER (cache-backed operations)")
 
 	// Create a lister by registering a no-op handler.
-	// Registering an actual handler will also return the lister.
-	lister, err := cmc.Inform(ctx, generic.InformerHandler[*corev1.ConfigMap]{}, nil)
+	// Registering an actual handler will also return the informer.
+	informer, err := cmc.Inform(ctx, generic.InformerHandler[*corev1.ConfigMap]{}, nil)
 	if err != nil {
 		log.Fatal("Error creating lister:", err)
 	}
+	lister := informer.Lister()
 	// Use lister to efficiently query from cache
 	allCMs, err := lister.List(labels.Everything())
 	if err != nil {
@@ -0,0 +1,216 @@
+// Package metrics provides Prometheus implementations of the generic.Metrics
+// and controller.Metrics interfaces.
+//
+//	reg := prometheus.NewRegistry()
+//	clientMetrics, _ := metrics.NewClientMetrics(reg)
+//	client = client.WithMetrics(clientMetrics)
+//
+//	controllerMetrics, _ := metrics.NewControllerMetrics(reg)
+//	ctrl := controller.New(client, reconciler, &controller.Options[*corev1.Pod]{
+//	    Name:    "pod-controller",
+//	    Metrics: controllerMetrics,
+//	})
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/imjasonh/client-go2/controller"
+	"github.com/imjasonh/client-go2/generic"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// ClientMetrics records generic.Client requests as Prometheus metrics.
+type ClientMetrics struct {
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	throttle *prometheus.HistogramVec
+}
+
+var _ generic.Metrics = (*ClientMetrics)(nil)
+
+// NewClientMetrics creates ClientMetrics and registers them with reg.
+// Registering with a Registerer that already has them is not an error; the
+// existing collectors are reused.
+func NewClientMetrics(reg prometheus.Registerer) (*ClientMetrics, error) {
+	labels := []string{"group", "version", "resource", "subresource", "verb"}
+	requests, err := register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "generic_client_requests_total",
+		Help: "Number of API requests made by generic clients, by resource, verb and HTTP status code.",
+	}, append(labels, "code")))
+	if err != nil {
+		return nil, err
+	}
+	latency, err := register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "generic_client_request_duration_seconds",
+		Help:    "Latency of API requests made by generic clients, by resource and verb.",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+	throttle, err := register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "generic_client_rate_limiter_duration_seconds",
+		Help:    "Time API requests made by generic clients spent waiting on the client-side rate limiter.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+	return &ClientMetrics{requests: requests, latency: latency, throttle: throttle}, nil
+}
+
+// ObserveRequest implements generic.Metrics.
+func (m *ClientMetrics) ObserveRequest(info generic.RequestInfo, code int, latency time.Duration) {
+	m.requests.WithLabelValues(info.GVR.Group, info.GVR.Version, info.GVR.Resource, info.Subresource, info.Verb, strconv.Itoa(code)).Inc()
+	m.latency.WithLabelValues(info.GVR.Group, info.GVR.Version, info.GVR.Resource, info.Subresource, info.Verb).Observe(latency.Seconds())
+}
+
+// ObserveThrottle implements generic.Metrics.
+func (m *ClientMetrics) ObserveThrottle(info generic.RequestInfo, delay time.Duration) {
+	m.throttle.WithLabelValues(info.GVR.Group, info.GVR.Version, info.GVR.Resource, info.Subresource, info.Verb).Observe(delay.Seconds())
+}
+
+// ControllerMetrics records controller queue and reconcile metrics as
+// Prometheus metrics, all labelled by controller name as "controller".
+type ControllerMetrics struct {
+	depth                   *prometheus.GaugeVec
+	adds                    *prometheus.CounterVec
+	latency                 *prometheus.HistogramVec
+	workDuration            *prometheus.HistogramVec
+	unfinished              *prometheus.GaugeVec
+	longestRunningProcessor *prometheus.GaugeVec
+	retries                 *prometheus.CounterVec
+	reconciles              *prometheus.CounterVec
+	reconcileDuration       *prometheus.HistogramVec
+}
+
+var _ controller.Metrics = (*ControllerMetrics)(nil)
+
+// NewControllerMetrics creates ControllerMetrics and registers them with reg.
+// Registering with a Registerer that already has them is not an error; the
+// existing collectors are reused.
+func NewControllerMetrics(reg prometheus.Registerer) (*ControllerMetrics, error) {
+	name := []string{"controller"}
+	durationBuckets := prometheus.ExponentialBuckets(0.001, 4, 10)
+	var m ControllerMetrics
+	var err error
+	if m.depth, err = register(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_workqueue_depth",
+		Help: "Current number of items waiting in the controller's workqueue.",
+	}, name)); err != nil {
+		return nil, err
+	}
+	if m.adds, err = register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_workqueue_adds_total",
+		Help: "Number of items added to the controller's workqueue.",
+	}, name)); err != nil {
+		return nil, err
+	}
+	if m.latency, err = register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_workqueue_queue_duration_seconds",
+		Help:    "How long items wait in the controller's workqueue before being processed.",
+		Buckets: durationBuckets,
+	}, name)); err != nil {
+		return nil, err
+	}
+	if m.workDuration, err = register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_workqueue_work_duration_seconds",
+		Help:    "How long processing an item from the controller's workqueue takes.",
+		Buckets: durationBuckets,
+	}, name)); err != nil {
+		return nil, err
+	}
+	if m.unfinished, err = register(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_workqueue_unfinished_work_seconds",
+		Help: "Total seconds of work in progress that has not yet been observed by work_duration.",
+	}, name)); err != nil {
+		return nil, err
+	}
+	if m.longestRunningProcessor, err = register(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_workqueue_longest_running_processor_seconds",
+		Help: "How long the longest running item has been processing.",
+	}, name)); err != nil {
+		return nil, err
+	}
+	if m.retries, err = register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_workqueue_retries_total",
+		Help: "Number of rate-limited retries in the controller's workqueue.",
+	}, name)); err != nil {
+		return nil, err
+	}
+	if m.reconciles, err = register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_reconcile_total",
+		Help: "Number of reconciles, by controller and outcome.",
+	}, []string{"controller", "outcome"})); err != nil {
+		return nil, err
+	}
+	if m.reconcileDuration, err = register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_reconcile_duration_seconds",
+		Help:    "How long reconciles take, by controller and outcome.",
+		Buckets: durationBuckets,
+	}, []string{"controller", "outcome"})); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ObserveReconcile implements controller.Metrics.
+func (m *ControllerMetrics) ObserveReconcile(name string, outcome controller.Outcome, duration time.Duration) {
+	m.reconciles.WithLabelValues(name, string(outcome)).Inc()
+	m.reconcileDuration.WithLabelValues(name, string(outcome)).Observe(duration.Seconds())
+}
+
+// NewDepthMetric implements workqueue.MetricsProvider.
+func (m *ControllerMetrics) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return m.depth.WithLabelValues(name)
+}
+
+// NewAddsMetric implements workqueue.MetricsProvider.
+func (m *ControllerMetrics) NewAddsMetric(name string) workqueue.CounterMetric {
+	return m.adds.WithLabelValues(name)
+}
+
+// NewLatencyMetric implements workqueue.MetricsProvider.
+func (m *ControllerMetrics) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return m.latency.WithLabelValues(name)
+}
+
+// NewWorkDurationMetric implements workqueue.MetricsProvider.
+func (m *ControllerMetrics) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return m.workDuration.WithLabelValues(name)
+}
+
+// NewUnfinishedWorkSecondsMetric implements workqueue.MetricsProvider.
+func (m *ControllerMetrics) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return m.unfinished.WithLabelValues(name)
+}
+
+// NewLongestRunningProcessorSecondsMetric implements workqueue.MetricsProvider.
+func (m *ControllerMetrics) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return m.longestRunningProcessor.WithLabelValues(name)
+}
+
+// NewRetriesMetric implements workqueue.MetricsProvider.
+func (m *ControllerMetrics) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return m.retries.WithLabelValues(name)
+}
+
+// register registers c with reg, returning the already-registered collector
+// if an equivalent one exists.
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) (C, error) {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing, nil
+			}
+		}
+		var zero C
+		return zero, err
+	}
+	return c, nil
+}
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imjasonh/client-go2/controller"
+	"github.com/imjasonh/client-go2/generic"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestClientMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewClientMetrics(reg)
+	if err != nil {
+		t.Fatalf("NewClientMetrics failed: %v", err)
+	}
+
+	client := generic.NewClientGVR[*corev1.ConfigMap](
+		schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		&rest.Config{Host: "http://test", Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(req.URL.Path, "/missing") {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(strings.NewReader(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)),
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"cm"}}`)),
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+			}, nil
+		})},
+	).WithMetrics(m)
+
+	ctx := context.Background()
+	for range 2 {
+		if _, err := client.Get(ctx, "default", "cm", nil); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+	}
+	if _, err := client.Get(ctx, "default", "missing", nil); err == nil {
+		t.Fatal("expected Get of missing configmap to fail")
+	}
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("", "v1", "configmaps", "", "get", "200")); got != 2 {
+		t.Errorf("expected 2 successful gets, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("", "v1", "configmaps", "", "get", "404")); got != 1 {
+		t.Errorf("expected 1 failed get, got %v", got)
+	}
+	if got := testutil.CollectAndCount(m.throttle); got != 1 {
+		t.Errorf("expected throttle histogram for get, got %d series", got)
+	}
+
+	// Registering again reuses the existing collectors.
+	again, err := NewClientMetrics(reg)
+	if err != nil {
+		t.Fatalf("second NewClientMetrics failed: %v", err)
+	}
+	if again.requests != m.requests {
+		t.Error("expected existing collectors to be reused")
+	}
+}
+
+func TestControllerMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewControllerMetrics(reg)
+	if err != nil {
+		t.Fatalf("NewControllerMetrics failed: %v", err)
+	}
+
+	queue := workqueue.NewTypedRateLimitingQueueWithConfig(
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+		workqueue.TypedRateLimitingQueueConfig[string]{Name: "test-controller", MetricsProvider: m},
+	)
+	defer queue.ShutDown()
+
+	queue.Add("default/a")
+	queue.Add("default/b")
+	queue.AddRateLimited("default/c")
+
+	if got := testutil.ToFloat64(m.depth.WithLabelValues("test-controller")); got != 2 {
+		t.Errorf("expected queue depth 2, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.retries.WithLabelValues("test-controller")); got != 1 {
+		t.Errorf("expected 1 retry, got %v", got)
+	}
+
+	m.ObserveReconcile("test-controller", controller.OutcomeSuccess, time.Millisecond)
+	m.ObserveReconcile("test-controller", controller.OutcomeSuccess, time.Millisecond)
+	m.ObserveReconcile("test-controller", controller.OutcomeError, time.Millisecond)
+	if got := testutil.ToFloat64(m.reconciles.WithLabelValues("test-controller", "success")); got != 2 {
+		t.Errorf("expected 2 successful reconciles, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.reconciles.WithLabelValues("test-controller", "error")); got != 1 {
+		t.Errorf("expected 1 failed reconcile, got %v", got)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, f := range families {
+		for _, metric := range f.GetMetric() {
+			var controllerName string
+			for _, l := range metric.GetLabel() {
+				if l.GetName() == "controller" {
+					controllerName = l.GetValue()
+				}
+			}
+			if controllerName != "test-controller" {
+				t.Errorf("%s: expected controller label %q, got labels %v", f.GetName(), "test-controller", metric.GetLabel())
+			}
+		}
+	}
+}